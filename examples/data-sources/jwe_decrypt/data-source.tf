@@ -0,0 +1,20 @@
+terraform {
+  required_providers {
+    jose = {
+      source = "registry.terraform.io/aiyor-tf/jose"
+    }
+  }
+}
+
+provider "jose" {}
+
+# A JWE committed to the repository, e.g. produced by jose_jwe_encrypt.
+data "jose_jwe_decrypt" "database_password" {
+  jwe         = file("./database-password.jwe")
+  private_key = file("../../resources/jwt_sign/ecdsa.key")
+}
+
+output "database_password" {
+  value     = data.jose_jwe_decrypt.database_password.plaintext
+  sensitive = true
+}
@@ -0,0 +1,35 @@
+terraform {
+  required_providers {
+    jose = {
+      source = "registry.terraform.io/aiyor-tf/jose"
+    }
+  }
+}
+
+provider "jose" {}
+
+resource "jose_jwks" "example" {
+  jwks_properties = [
+    {
+      kid        = "this-is-a-key-id-for-ecdsa-key"
+      public_key = file("../../resources/jwks/ecdsa.key.pub")
+    },
+    {
+      kid        = "this-is-a-key-id-for-rsa-key"
+      public_key = file("../../resources/jwks/rsa.key.pub")
+    },
+  ]
+}
+
+# Convert every key of a JWK Set back to PEM, keyed by kid.
+data "jose_jwk_pem" "example" {
+  jwks = jose_jwks.example.jwks
+}
+
+output "ecdsa_public_key_pem" {
+  value = data.jose_jwk_pem.example.keys["this-is-a-key-id-for-ecdsa-key"].public_key_pem
+}
+
+output "key_types" {
+  value = { for kid, key in data.jose_jwk_pem.example.keys : kid => key.kty }
+}
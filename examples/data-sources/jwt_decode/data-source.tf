@@ -0,0 +1,36 @@
+terraform {
+  required_providers {
+    jose = {
+      source = "registry.terraform.io/aiyor-tf/jose"
+    }
+  }
+}
+
+provider "jose" {}
+
+resource "jose_jwt_sign" "ed25519" {
+  private_key = file("../../resources/jwt_sign/ed25519.key")
+  kid         = "this-is-a-key-id-for-ed25519-key"
+  claims_json = jsonencode({
+    "sub" : "1234567890",
+    "iat" : 1516239022,
+    "exp" : 1516242622,
+  })
+}
+
+# The signature is not verified, use jose_jwt_verify for that.
+data "jose_jwt_decode" "ed25519" {
+  jwt = jose_jwt_sign.ed25519.jwt
+}
+
+output "subject" {
+  value = data.jose_jwt_decode.ed25519.claims.sub
+}
+
+output "header" {
+  value = data.jose_jwt_decode.ed25519.header
+}
+
+output "expires_at" {
+  value = data.jose_jwt_decode.ed25519.expires_at
+}
@@ -0,0 +1,51 @@
+terraform {
+  required_providers {
+    jose = {
+      source = "registry.terraform.io/aiyor-tf/jose"
+    }
+  }
+}
+
+provider "jose" {}
+
+resource "jose_jwks" "example" {
+  jwks_properties = [
+    {
+      kid        = "this-is-a-key-id-for-ecdsa-key"
+      public_key = file("../../resources/jwks/ecdsa.key.pub")
+    },
+  ]
+}
+
+resource "jose_jwt_sign" "ecdsa" {
+  private_key = file("../../resources/jwt_sign/ecdsa.key")
+  kid         = "this-is-a-key-id-for-ecdsa-key"
+  claims_json = jsonencode({ "iss" : "https://example.com", "aud" : "https://example.com" })
+}
+
+# Fails the plan when the token does not verify.
+data "jose_jwt_verify" "example" {
+  jwt      = jose_jwt_sign.ecdsa.jwt
+  jwks     = jose_jwks.example.jwks
+  issuer   = "https://example.com"
+  audience = "https://example.com"
+  leeway   = "30s"
+}
+
+# Reports the result instead, for use in a check block.
+data "jose_jwt_verify" "check" {
+  jwt             = jose_jwt_sign.ecdsa.jwt
+  jwks            = jose_jwks.example.jwks
+  fail_on_invalid = false
+}
+
+check "jwt_matches_jwks" {
+  assert {
+    condition     = data.jose_jwt_verify.check.valid
+    error_message = data.jose_jwt_verify.check.reason
+  }
+}
+
+output "claims" {
+  value = jsondecode(data.jose_jwt_verify.example.claims_json)
+}
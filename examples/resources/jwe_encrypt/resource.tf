@@ -0,0 +1,44 @@
+terraform {
+  required_providers {
+    jose = {
+      source = "registry.terraform.io/aiyor-tf/jose"
+    }
+  }
+}
+
+provider "jose" {}
+
+resource "jose_jwe_encrypt" "rsa" {
+  plaintext  = "this-is-a-secret"
+  public_key = file("../../../internal/provider/fixtures/rsa-pub.pem")
+  alg        = "RSA-OAEP-256" # Optional, defaults by key type.
+  enc        = "A256GCM"      # Optional, defaults to "A256GCM".
+  kid        = "this-is-a-key-id-for-rsa-key"
+}
+
+resource "jose_jwk" "ecdsa" {
+  kid        = "this-is-a-key-id-for-ecdsa-key"
+  public_key = file("../../../internal/provider/fixtures/ecdsa-pub.pem")
+  use        = "enc"
+}
+
+# Nested JWT: sign first, then encrypt to the recipient JWK.
+resource "jose_jwt_sign" "ed25519" {
+  private_key = file("../jwt_sign/ed25519.key")
+  kid         = "this-is-a-key-id-for-ed25519-key"
+  claims_json = jsonencode({ "sub" : "1234567890" })
+}
+
+resource "jose_jwe_encrypt" "nested" {
+  plaintext  = jose_jwt_sign.ed25519.jwt
+  public_jwk = jose_jwk.ecdsa.jwk
+  cty        = "JWT"
+}
+
+output "jwe_rsa" {
+  value = jose_jwe_encrypt.rsa.jwe
+}
+
+output "jwe_nested" {
+  value = jose_jwe_encrypt.nested.jwe
+}
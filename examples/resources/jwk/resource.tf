@@ -10,7 +10,7 @@ provider "jose" {}
 
 resource "jose_jwk" "example_rsa" {
   kid        = "this-is-a-key-id-for-rsa-key"
-  alg        = "RS256" # Optional, defaults to "RS256". Only applicable for RSA keys.
+  alg        = "RS256" # Optional, defaults to "RS256" for RSA signing keys.
   public_key = file("../../../internal/provider/fixtures/rsa-pub.pem")
   use        = "sig"
 }
@@ -19,6 +19,11 @@ resource "jose_jwk" "example_ecdsa" {
   kid        = "this-is-a-key-id-for-ecdsa-key"
   public_key = file("../../../internal/provider/fixtures/ecdsa-pub.pem")
   use        = "sig"
+
+  # Changing any value creates a new JWK.
+  keepers = {
+    rotation = "2026-10"
+  }
 }
 
 resource "jose_jwk" "example_ed25519" {
@@ -27,6 +32,59 @@ resource "jose_jwk" "example_ed25519" {
   use        = "sig"
 }
 
+# secp256k1 keys are signing keys for ES256K (RFC 8812).
+resource "jose_jwk" "example_secp256k1" {
+  public_key = file("../../../internal/provider/fixtures/secp256k1-pub.pem")
+  use        = "sig"
+}
+
+# An encryption key, for jose_jwe_encrypt recipients. The algorithm defaults to
+# "RSA-OAEP-256" for RSA keys and "ECDH-ES+A256KW" for ECDSA keys.
+resource "jose_jwk" "example_encryption" {
+  kid        = "this-is-a-key-id-for-rsa-encryption-key"
+  alg        = "RSA-OAEP"
+  public_key = file("../../../internal/provider/fixtures/rsa-pub.pem")
+  use        = "enc"
+}
+
+# X25519 and X448 keys can only be used for encryption.
+resource "jose_jwk" "example_x25519" {
+  public_key = file("../../../internal/provider/fixtures/x25519-pub.pem")
+  use        = "enc"
+}
+
+# key_ops can be used instead of use, for consumers such as WebCrypto.
+resource "jose_jwk" "example_key_ops" {
+  public_key = file("../../../internal/provider/fixtures/ecdsa-pub.pem")
+  key_ops    = ["verify"]
+}
+
+# The x5c, x5t and x5t#S256 members are populated from the certificate chain,
+# and the public key is taken from the leaf certificate.
+resource "jose_jwk" "example_certificate" {
+  kid         = "this-is-a-key-id-for-ecdsa-key"
+  certificate = file("../../../internal/provider/fixtures/ecdsa-cert.pem")
+  use         = "sig"
+
+  # Optional, checked during plan.
+  trust_anchors             = file("../../../internal/provider/fixtures/ca.pem")
+  certificate_expiry_window = "720h"
+  certificate_validation    = "warning" # Optional, defaults to "error".
+}
+
+# The key ID defaults to the RFC 7638 SHA-256 thumbprint of the key.
+resource "jose_jwk" "example_thumbprint" {
+  public_key = file("../../../internal/provider/fixtures/ed25519-pub.pem")
+}
+
+# Private JWK for an authorization server, with the public JWK for its
+# clients derived from the same key.
+resource "jose_jwk" "example_private_ecdsa" {
+  kid         = "this-is-a-key-id-for-ecdsa-key"
+  private_key = file("../jwt_sign/ecdsa.key")
+  use         = "sig"
+}
+
 output "jwk_rsa" {
   value = jsondecode(jose_jwk.example_rsa.jwk)
 }
@@ -39,6 +97,14 @@ output "jwk_ed25519" {
   value = jsondecode(jose_jwk.example_ed25519.jwk)
 }
 
+output "jwk_certificate" {
+  value = jsondecode(jose_jwk.example_certificate.jwk)
+}
+
+output "jwk_thumbprint_uri" {
+  value = jose_jwk.example_thumbprint.thumbprint_uri
+}
+
 output "jwk_rsa_b64" {
   value = jose_jwk.example_rsa.jwk_b64
 }
@@ -50,3 +116,8 @@ output "jwk_ecdsa_b64" {
 output "jwk_ed25519_b64" {
   value = jose_jwk.example_ed25519.jwk_b64
 }
+
+output "jwk_private_ecdsa" {
+  value     = jose_jwk.example_private_ecdsa.private_jwk
+  sensitive = true
+}
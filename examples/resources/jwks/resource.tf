@@ -32,6 +32,11 @@ resource "jose_jwks" "example" {
       use        = "sig"
     },
   ]
+
+  # Changing any value creates a new JWK Set.
+  keepers = {
+    rotation = "2026-10"
+  }
 }
 
 output "jwks" {
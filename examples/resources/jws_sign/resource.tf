@@ -0,0 +1,68 @@
+terraform {
+  required_providers {
+    jose = {
+      source = "registry.terraform.io/aiyor-tf/jose"
+    }
+  }
+}
+
+provider "jose" {}
+
+resource "jose_jws_sign" "rsa" {
+  payload     = jsonencode({ "version" : "1.2.3", "artifacts" : ["app.tar.gz"] })
+  private_key = file("../jwt_sign/rsa.key")
+  alg         = "RS384" # Optional, only applicable to RSA keys.
+  kid         = "this-is-a-key-id-for-rsa-key"
+  cty         = "application/json"
+}
+
+resource "jose_jws_sign" "binary" {
+  payload_base64 = filebase64("../../../internal/provider/fixtures/ecdsa-pub.pem")
+  private_key    = file("../jwt_sign/ed25519.key")
+  typ            = "application/octet-stream"
+}
+
+# Key rotation: sign with both the old and the new key, so verifiers
+# holding either JWK Set accept the document.
+resource "jose_jws_sign" "rotation" {
+  payload       = jsonencode({ "version" : "1.2.3" })
+  serialization = "json" # Optional, defaults to "compact".
+  signatures = [
+    {
+      private_key = file("../jwt_sign/rsa.key")
+      kid         = "this-is-the-old-key-id"
+    },
+    {
+      private_key              = file("../jwt_sign/ecdsa.key")
+      kid                      = "this-is-the-new-key-id"
+      protected_headers_json   = jsonencode({ "x-generation" : 2 })
+      unprotected_headers_json = jsonencode({ "x-rotation" : "new" })
+    },
+  ]
+}
+
+# Detached signature over the unencoded request body (RFC 7797), as
+# required by open banking APIs.
+resource "jose_jws_sign" "detached" {
+  payload           = jsonencode({ "amount" : "10.00", "currency" : "GBP" })
+  private_key       = file("../jwt_sign/ecdsa.key")
+  kid               = "this-is-a-key-id-for-ecdsa-key"
+  detached          = true # Optional, defaults to false.
+  unencoded_payload = true # Optional, defaults to false.
+}
+
+output "jws_rsa" {
+  value = jose_jws_sign.rsa.jws
+}
+
+output "jws_binary" {
+  value = jose_jws_sign.binary.jws
+}
+
+output "jws_rotation" {
+  value = jose_jws_sign.rotation.jws
+}
+
+output "jws_detached" {
+  value = jose_jws_sign.detached.jws
+}
@@ -14,8 +14,6 @@ locals {
     "iss" : "https://example.com",
     "sub" : "1234567890",
     "aud" : "https://example.com",
-    "iat" : 1516239022,
-    "exp" : 1516239022,
     "custom_list" : [
       "foo",
       "bar"
@@ -28,6 +26,20 @@ resource "jose_jwt_sign" "rsa" {
   alg         = "RS256" # Optional
   kid         = "this-is-a-key-id-for-rsa-key"
   claims_json = jsonencode(local.claims)
+
+  # Optional, the iat, nbf, exp and jti claims are added when signing.
+  set_issued_at     = true
+  not_before_offset = "-30s"
+  expires_in        = "1h"
+  generate_jti      = true
+
+  # Optional, the JWT is signed again when it expires within 15 minutes.
+  early_renewal = "15m"
+
+  # Optional, changing any value signs a new JWT.
+  keepers = {
+    deploy_id = "42"
+  }
 }
 
 resource "jose_jwt_sign" "ecdsa" {
@@ -42,11 +54,48 @@ resource "jose_jwt_sign" "ed25519" {
   claims_json = jsonencode(local.claims)
 }
 
+# Encrypted private keys, in encrypted PKCS #8 or legacy encrypted PEM format,
+# are decrypted with the passphrase.
+variable "private_key_passphrase" {
+  type      = string
+  sensitive = true
+}
+
+resource "jose_jwt_sign" "encrypted" {
+  private_key            = file("../../../internal/provider/fixtures/ecdsa-encrypted.pem")
+  private_key_passphrase = var.private_key_passphrase
+  claims_json            = jsonencode(local.claims)
+}
+
+# Signs with a private JWK, using its alg and kid. A private JWK Set can be
+# given with private_jwks instead, the key being selected by kid.
+resource "jose_jwk" "private" {
+  kid         = "this-is-a-key-id-for-ecdsa-key"
+  private_key = file("./ecdsa.key")
+}
+
+resource "jose_jwt_sign" "jwk" {
+  private_jwk = jose_jwk.private.private_jwk
+  claims_json = jsonencode(local.claims)
+}
+
+# Signs with HMAC, using a shared secret. The secret can also be given in
+# base64 or as an "oct" JWK with secret_encoding.
+resource "jose_jwt_sign" "hmac" {
+  secret      = "this-is-a-shared-secret-of-at-least-32-bytes"
+  alg         = "HS256" # Optional
+  claims_json = jsonencode(local.claims)
+}
+
 output "rsa_jwt" {
   value     = jose_jwt_sign.rsa.jwt
   sensitive = true
 }
 
+output "rsa_jwt_expires_at" {
+  value = jose_jwt_sign.rsa.expires_at
+}
+
 output "ecdsa_jwt" {
   value     = jose_jwt_sign.ecdsa.jwt
   sensitive = true
@@ -56,3 +105,8 @@ output "ed25519_jwt" {
   value     = jose_jwt_sign.ed25519.jwt
   sensitive = true
 }
+
+output "hmac_jwt" {
+  value     = jose_jwt_sign.hmac.jwt
+  sensitive = true
+}
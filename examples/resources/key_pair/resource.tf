@@ -0,0 +1,46 @@
+terraform {
+  required_providers {
+    jose = {
+      source = "registry.terraform.io/aiyor-tf/jose"
+    }
+  }
+}
+
+provider "jose" {}
+
+resource "jose_key_pair" "rsa" {
+  algorithm = "RSA"
+  rsa_bits  = 3072    # Optional, defaults to 2048.
+  alg       = "RS256" # Optional, defaults to "RS256" for RSA signing keys.
+}
+
+resource "jose_key_pair" "ecdsa" {
+  algorithm   = "ECDSA"
+  ecdsa_curve = "P-384" # Optional, defaults to "P-256".
+
+  # Optional, defaults to the JWK thumbprint.
+  kid = "this-is-a-key-id-for-ecdsa-key"
+}
+
+resource "jose_key_pair" "ed25519" {
+  algorithm = "Ed25519"
+}
+
+# The generated key signs directly, no PEM strings to pass around.
+resource "jose_jwt_sign" "ed25519" {
+  private_key = jose_key_pair.ed25519.private_key_pem
+  kid         = jose_key_pair.ed25519.kid
+  claims_json = jsonencode({ "sub" : "1234567890" })
+}
+
+output "public_jwk_rsa" {
+  value = jsondecode(jose_key_pair.rsa.public_jwk)
+}
+
+output "kid_ecdsa" {
+  value = jose_key_pair.ecdsa.kid
+}
+
+output "jwt_ed25519" {
+  value = jose_jwt_sign.ed25519.jwt
+}
@@ -0,0 +1,168 @@
+// Copyright (c) HashiCorp, Inc.
+// Copyright (c) Tze Liang
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/hashicorp/terraform-plugin-framework-validators/datasourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var (
+	_ datasource.DataSource                     = &joseJweDecryptDataSource{}
+	_ datasource.DataSourceWithConfigValidators = &joseJweDecryptDataSource{}
+)
+
+func NewJoseJweDecryptDataSource() datasource.DataSource {
+	return &joseJweDecryptDataSource{}
+}
+
+// joseJweDecryptDataSource defines the data source implementation.
+type joseJweDecryptDataSource struct{}
+
+// joseJweDecryptDataSourceModel describes the data source data model.
+type joseJweDecryptDataSourceModel struct {
+	JWE        types.String `tfsdk:"jwe"`
+	PrivateKey types.String `tfsdk:"private_key"`
+	PrivateJWK types.String `tfsdk:"private_jwk"`
+	KID        types.String `tfsdk:"kid"`
+	Alg        types.String `tfsdk:"alg"`
+	Enc        types.String `tfsdk:"enc"`
+	CTY        types.String `tfsdk:"cty"`
+	Plaintext  types.String `tfsdk:"plaintext"`
+}
+
+func (d *joseJweDecryptDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_jwe_decrypt"
+}
+
+func (d *joseJweDecryptDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Decrypts a JWE in compact or JSON serialization. Supports RSA and ECDSA private keys.",
+		Attributes: map[string]schema.Attribute{
+			"jwe": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The JWE to decrypt, in compact or JSON serialization.",
+			},
+			"private_key": schema.StringAttribute{
+				Optional:            true,
+				Sensitive:           true,
+				MarkdownDescription: "Private key in PEM format. Exactly one of `private_key` or `private_jwk` must be set.",
+			},
+			"private_jwk": schema.StringAttribute{
+				Optional:            true,
+				Sensitive:           true,
+				MarkdownDescription: "Private key as a JWK in JSON format. Exactly one of `private_key` or `private_jwk` must be set.",
+			},
+			"kid": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Expected key ID. Decryption fails when the JWE was encrypted for a different key ID. Defaults to the `kid` of `private_jwk` when set, otherwise it is read from the JWE header.",
+			},
+			"alg": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Key management algorithm from the JWE header.",
+			},
+			"enc": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Content encryption algorithm from the JWE header.",
+			},
+			"cty": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Content type from the JWE header, if any.",
+			},
+			"plaintext": schema.StringAttribute{
+				Computed:            true,
+				Sensitive:           true,
+				MarkdownDescription: "The decrypted plaintext.",
+			},
+		},
+	}
+}
+
+func (d *joseJweDecryptDataSource) ConfigValidators(ctx context.Context) []datasource.ConfigValidator {
+	return []datasource.ConfigValidator{
+		datasourcevalidator.ExactlyOneOf(
+			path.MatchRoot("private_key"),
+			path.MatchRoot("private_jwk"),
+		),
+	}
+}
+
+func (d *joseJweDecryptDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+}
+
+func (d *joseJweDecryptDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var (
+		data       joseJweDecryptDataSourceModel
+		privateKey PrivateKey
+		err        error
+	)
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	kid := data.KID.ValueString()
+
+	// Parse the private key from either PEM or JWK
+	if !data.PrivateJWK.IsNull() {
+		var jwk *jose.JSONWebKey
+		privateKey, jwk, err = parsePrivateJWK([]byte(data.PrivateJWK.ValueString()), types.StringNull())
+		if err != nil {
+			resp.Diagnostics.AddError("Invalid private JWK", err.Error())
+			return
+		}
+		if kid == "" {
+			kid = jwk.KeyID
+		}
+	} else {
+		privateKey, err = parsePrivateKey([]byte(data.PrivateKey.ValueString()), types.StringNull())
+		if err != nil {
+			resp.Diagnostics.AddError("Invalid private key", err.Error())
+			return
+		}
+	}
+
+	plaintext, header, err := decryptJWE(data.JWE.ValueString(), privateKey, kid)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to decrypt JWE", err.Error())
+		return
+	}
+
+	if kid == "" {
+		kid = header.KeyID
+	}
+
+	data.KID = types.StringValue(kid)
+	data.Alg = types.StringValue(header.Algorithm)
+	data.Enc = types.StringNull()
+	if enc, ok := header.ExtraHeaders["enc"].(string); ok {
+		data.Enc = types.StringValue(enc)
+	}
+	data.CTY = types.StringNull()
+	if cty, ok := header.ExtraHeaders[jose.HeaderContentType].(string); ok {
+		data.CTY = types.StringValue(cty)
+	}
+	data.Plaintext = types.StringValue(string(plaintext))
+
+	// Write logs using the tflog package
+	// Documentation: https://terraform.io/plugin/log
+	tflog.Trace(ctx, "read a data source")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,59 @@
+// Copyright (c) HashiCorp, Inc.
+// Copyright (c) Tze Liang
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccJoseJweDecryptDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config: `
+					resource "jose_jwe_encrypt" "test" {
+						plaintext  = "this-is-a-secret"
+						public_key = file("./fixtures/ecdsa-pub.pem")
+						kid        = "this-is-a-key-id-for-ecdsa-key"
+					}
+
+					data "jose_jwe_decrypt" "test" {
+						jwe         = jose_jwe_encrypt.test.jwe
+						private_key = file("./fixtures/ecdsa.pem")
+					}
+				`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.jose_jwe_decrypt.test", "plaintext", "this-is-a-secret"),
+					resource.TestCheckResourceAttr("data.jose_jwe_decrypt.test", "kid", "this-is-a-key-id-for-ecdsa-key"),
+					resource.TestCheckResourceAttr("data.jose_jwe_decrypt.test", "alg", "ECDH-ES+A256KW"),
+					resource.TestCheckResourceAttr("data.jose_jwe_decrypt.test", "enc", "A256GCM"),
+				),
+			},
+			// Key ID mismatch
+			{
+				Config: `
+					resource "jose_jwe_encrypt" "test" {
+						plaintext  = "this-is-a-secret"
+						public_key = file("./fixtures/ecdsa-pub.pem")
+						kid        = "this-is-a-key-id-for-ecdsa-key"
+					}
+
+					data "jose_jwe_decrypt" "test" {
+						jwe         = jose_jwe_encrypt.test.jwe
+						private_key = file("./fixtures/ecdsa.pem")
+						kid         = "some-other-key-id"
+					}
+				`,
+				ExpectError: regexp.MustCompile(`JWE was encrypted for key ID`),
+			},
+		},
+	})
+}
@@ -0,0 +1,164 @@
+// Copyright (c) HashiCorp, Inc.
+// Copyright (c) Tze Liang
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"crypto"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var (
+	_ resource.Resource                     = &joseJweEncryptResource{}
+	_ resource.ResourceWithConfigValidators = &joseJweEncryptResource{}
+)
+
+func NewJoseJweEncryptResource() resource.Resource {
+	return &joseJweEncryptResource{}
+}
+
+// joseJweEncryptResource defines the resource implementation.
+type joseJweEncryptResource struct{}
+
+// joseJweEncryptResourceModel describes the resource data model.
+type joseJweEncryptResourceModel struct {
+	Plaintext types.String `tfsdk:"plaintext"`
+	PublicKey types.String `tfsdk:"public_key"`
+	PublicJWK types.String `tfsdk:"public_jwk"`
+	Alg       types.String `tfsdk:"alg"`
+	Enc       types.String `tfsdk:"enc"`
+	KID       types.String `tfsdk:"kid"`
+	CTY       types.String `tfsdk:"cty"`
+	JWE       types.String `tfsdk:"jwe"`
+}
+
+func (r *joseJweEncryptResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_jwe_encrypt"
+}
+
+func (r *joseJweEncryptResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Creates a JWE in compact serialization. Supports RSA and ECDSA recipient keys.",
+		Attributes:          jweEncryptSchema,
+	}
+}
+
+func (r *joseJweEncryptResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		resourcevalidator.ExactlyOneOf(
+			path.MatchRoot("public_key"),
+			path.MatchRoot("public_jwk"),
+		),
+	}
+}
+
+func (r *joseJweEncryptResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+}
+
+func (r *joseJweEncryptResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var (
+		data   joseJweEncryptResourceModel
+		pubKey crypto.PublicKey
+		kid    string
+	)
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Parse the recipient key from either PEM or JWK
+	if !data.PublicJWK.IsNull() {
+		jwk, err := parsePublicJWK([]byte(data.PublicJWK.ValueString()))
+		if err != nil {
+			resp.Diagnostics.AddError("Invalid public JWK", err.Error())
+			return
+		}
+		pubKey = jwk.Key
+		kid = jwk.KeyID
+	} else {
+		key, err := parsePublicKey([]byte(data.PublicKey.ValueString()))
+		if err != nil {
+			resp.Diagnostics.AddError("Invalid public key", err.Error())
+			return
+		}
+		pubKey = key
+	}
+
+	if data.KID.ValueString() != "" {
+		kid = data.KID.ValueString()
+	}
+
+	alg, err := jweKeyAlgorithm(pubKey, data.Alg.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid key management algorithm", err.Error())
+		return
+	}
+
+	token, err := encryptJWE([]byte(data.Plaintext.ValueString()), pubKey, alg, data.Enc.ValueString(), kid, data.CTY.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to encrypt JWE", err.Error())
+		return
+	}
+
+	data.Alg = types.StringValue(alg)
+	data.JWE = types.StringValue(token)
+
+	// Write logs using the tflog package
+	// Documentation: https://terraform.io/plugin/log
+	tflog.Trace(ctx, "created a resource")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *joseJweEncryptResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data joseJweEncryptResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *joseJweEncryptResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data joseJweEncryptResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *joseJweEncryptResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data joseJweEncryptResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
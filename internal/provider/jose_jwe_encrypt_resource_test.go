@@ -0,0 +1,37 @@
+// Copyright (c) HashiCorp, Inc.
+// Copyright (c) Tze Liang
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccJoseJweEncryptResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: `
+					resource "jose_jwe_encrypt" "test" {
+						plaintext  = "this-is-a-secret"
+						public_key = file("./fixtures/rsa-pub.pem")
+						kid        = "this-is-a-key-id-for-rsa-key"
+					}
+				`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("jose_jwe_encrypt.test", "alg", "RSA-OAEP-256"),
+					resource.TestCheckResourceAttr("jose_jwe_encrypt.test", "enc", "A256GCM"),
+					resource.TestMatchResourceAttr("jose_jwe_encrypt.test", "jwe", regexp.MustCompile(`^[\w-]+\.[\w-]+\.[\w-]+\.[\w-]+\.[\w-]+$`)),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
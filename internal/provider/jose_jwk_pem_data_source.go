@@ -0,0 +1,207 @@
+// Copyright (c) HashiCorp, Inc.
+// Copyright (c) Tze Liang
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"crypto"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/datasourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var (
+	_ datasource.DataSource                     = &joseJwkPemDataSource{}
+	_ datasource.DataSourceWithConfigValidators = &joseJwkPemDataSource{}
+)
+
+func NewJoseJwkPemDataSource() datasource.DataSource {
+	return &joseJwkPemDataSource{}
+}
+
+// joseJwkPemDataSource defines the data source implementation.
+type joseJwkPemDataSource struct{}
+
+// joseJwkPemDataSourceModel describes the data source data model.
+type joseJwkPemDataSourceModel struct {
+	JWK  types.String                  `tfsdk:"jwk"`
+	JWKS types.String                  `tfsdk:"jwks"`
+	Keys map[string]joseJwkPemKeyModel `tfsdk:"keys"`
+}
+
+// joseJwkPemKeyModel describes a key in the keys attribute.
+type joseJwkPemKeyModel struct {
+	PublicKeyPEM types.String `tfsdk:"public_key_pem"`
+	Kty          types.String `tfsdk:"kty"`
+	Crv          types.String `tfsdk:"crv"`
+	Size         types.Int64  `tfsdk:"size"`
+	Alg          types.String `tfsdk:"alg"`
+	Use          types.String `tfsdk:"use"`
+}
+
+func (d *joseJwkPemDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_jwk_pem"
+}
+
+func (d *joseJwkPemDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Converts the keys of a JWK or a JWK Set into public keys in PEM format, keyed by key ID. This is the inverse of `jose_jwk` and `jose_jwks`. Supports RSA, ECDSA (including secp256k1) and EdDSA keys.",
+		Attributes: map[string]schema.Attribute{
+			"jwk": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "JWK in JSON format. Only its public part is converted. Exactly one of `jwk` or `jwks` must be set.",
+			},
+			"jwks": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "JWK Set in JSON format. Only the public part of its keys is converted. Keys that cannot be converted, such as symmetric keys, and keys with an already used key ID are skipped with a warning. Exactly one of `jwk` or `jwks` must be set.",
+			},
+			"keys": schema.MapNestedAttribute{
+				Computed:            true,
+				MarkdownDescription: "The keys keyed by their `kid`, or by their RFC 7638 SHA-256 thumbprint when they have no `kid`.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"public_key_pem": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "The public key in PEM (PKIX) format.",
+						},
+						"kty": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "The key type: `RSA`, `EC` or `OKP`.",
+						},
+						"crv": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "The curve of EC and OKP keys. Null for RSA keys.",
+						},
+						"size": schema.Int64Attribute{
+							Computed:            true,
+							MarkdownDescription: "The key size in bits: the modulus size for RSA keys and the curve size otherwise.",
+						},
+						"alg": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "The `alg` member of the JWK, if any.",
+						},
+						"use": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "The `use` member of the JWK, if any.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *joseJwkPemDataSource) ConfigValidators(ctx context.Context) []datasource.ConfigValidator {
+	return []datasource.ConfigValidator{
+		datasourcevalidator.ExactlyOneOf(
+			path.MatchRoot("jwk"),
+			path.MatchRoot("jwks"),
+		),
+	}
+}
+
+func (d *joseJwkPemDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+}
+
+func (d *joseJwkPemDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var (
+		data   joseJwkPemDataSourceModel
+		jwkSet JWKSet
+	)
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !data.JWK.IsNull() {
+		jwkSet.Keys = []json.RawMessage{json.RawMessage(data.JWK.ValueString())}
+	} else if err := json.Unmarshal([]byte(data.JWKS.ValueString()), &jwkSet); err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("jwks"), "Invalid JWK Set", err.Error())
+		return
+	}
+
+	// A JWK Set published by an identity provider may contain keys that cannot
+	// be converted, such as symmetric keys.  They are skipped with a warning,
+	// as are keys with an already used key ID.
+	data.Keys = make(map[string]joseJwkPemKeyModel, len(jwkSet.Keys))
+	for i, key := range jwkSet.Keys {
+		kid, pemKey, err := jwkPemKey(key)
+		if err != nil {
+			if !data.JWK.IsNull() {
+				resp.Diagnostics.AddAttributeError(path.Root("jwk"), "Invalid JWK", err.Error())
+				return
+			}
+			resp.Diagnostics.AddAttributeWarning(path.Root("jwks"), "Skipped JWK", fmt.Sprintf("key %d: %s", i, err))
+			continue
+		}
+		if _, ok := data.Keys[kid]; ok {
+			resp.Diagnostics.AddAttributeWarning(path.Root("jwks"), "Skipped JWK", fmt.Sprintf("key %d: more than one key with key ID %q, only the first one is kept", i, kid))
+			continue
+		}
+
+		data.Keys[kid] = pemKey
+	}
+
+	// Write logs using the tflog package
+	// Documentation: https://terraform.io/plugin/log
+	tflog.Trace(ctx, "read a data source")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// Convert the public part of a JWK into PEM format, returning its key ID, or
+// its RFC 7638 SHA-256 thumbprint when it has none.
+func jwkPemKey(key json.RawMessage) (string, joseJwkPemKeyModel, error) {
+	jwk, err := parsePublicJWK(key)
+	if err != nil {
+		return "", joseJwkPemKeyModel{}, err
+	}
+
+	kid := jwk.KeyID
+	if kid == "" {
+		if kid, err = jwkThumbprint(jwk.Key, crypto.SHA256); err != nil {
+			return "", joseJwkPemKeyModel{}, fmt.Errorf("failed to compute JWK thumbprint: %w", err)
+		}
+	}
+
+	pubPEM, err := encodePublicKey(jwk.Key)
+	if err != nil {
+		return "", joseJwkPemKeyModel{}, fmt.Errorf("failed to encode public key: %w", err)
+	}
+	kty, crv, size, err := describePublicKey(jwk.Key)
+	if err != nil {
+		return "", joseJwkPemKeyModel{}, err
+	}
+
+	return kid, joseJwkPemKeyModel{
+		PublicKeyPEM: types.StringValue(string(pubPEM)),
+		Kty:          types.StringValue(kty),
+		Crv:          stringOrNull(crv),
+		Size:         types.Int64Value(int64(size)),
+		Alg:          stringOrNull(jwk.Algorithm),
+		Use:          stringOrNull(jwk.Use),
+	}, nil
+}
+
+// Convert an optional string into a null value when it is empty.
+func stringOrNull(s string) types.String {
+	if s == "" {
+		return types.StringNull()
+	}
+
+	return types.StringValue(s)
+}
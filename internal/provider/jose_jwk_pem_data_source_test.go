@@ -0,0 +1,93 @@
+// Copyright (c) HashiCorp, Inc.
+// Copyright (c) Tze Liang
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccJoseJwkPemDataSource(t *testing.T) {
+	ecdsaPub, err := os.ReadFile("./fixtures/ecdsa-pub.pem")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config: `
+					resource "jose_jwks" "test" {
+						jwks_properties = [
+							{
+								kid        = "this-is-a-key-id-for-ecdsa-key"
+								public_key = file("./fixtures/ecdsa-pub.pem")
+							},
+							{
+								kid        = "this-is-a-key-id-for-rsa-key"
+								public_key = file("./fixtures/rsa-pub.pem")
+							},
+						]
+					}
+
+					resource "jose_jwk" "ed25519" {
+						public_key = file("./fixtures/ed25519-pub.pem")
+					}
+
+					data "jose_jwk_pem" "jwks" {
+						jwks = jose_jwks.test.jwks
+					}
+
+					data "jose_jwk_pem" "no_kid" {
+						jwk = jsonencode({ for k, v in jsondecode(jose_jwk.ed25519.jwk) : k => v if k != "kid" })
+					}
+				`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.jose_jwk_pem.jwks", "keys.%", "2"),
+					resource.TestCheckResourceAttr("data.jose_jwk_pem.jwks", "keys.this-is-a-key-id-for-ecdsa-key.public_key_pem", string(ecdsaPub)),
+					resource.TestCheckResourceAttr("data.jose_jwk_pem.jwks", "keys.this-is-a-key-id-for-ecdsa-key.kty", "EC"),
+					resource.TestCheckResourceAttr("data.jose_jwk_pem.jwks", "keys.this-is-a-key-id-for-ecdsa-key.crv", "P-256"),
+					resource.TestCheckResourceAttr("data.jose_jwk_pem.jwks", "keys.this-is-a-key-id-for-ecdsa-key.size", "256"),
+					resource.TestCheckResourceAttr("data.jose_jwk_pem.jwks", "keys.this-is-a-key-id-for-ecdsa-key.alg", "ES256"),
+					resource.TestCheckResourceAttr("data.jose_jwk_pem.jwks", "keys.this-is-a-key-id-for-rsa-key.kty", "RSA"),
+					resource.TestCheckNoResourceAttr("data.jose_jwk_pem.jwks", "keys.this-is-a-key-id-for-rsa-key.crv"),
+					resource.TestCheckResourceAttr("data.jose_jwk_pem.jwks", "keys.this-is-a-key-id-for-rsa-key.size", "4096"),
+					resource.TestCheckResourceAttr("data.jose_jwk_pem.no_kid", "keys.4ifRr_AhTCPr0pVSDHDM1-BXv_Jb-l6ccFqedPdu984.kty", "OKP"),
+					resource.TestCheckResourceAttr("data.jose_jwk_pem.no_kid", "keys.4ifRr_AhTCPr0pVSDHDM1-BXv_Jb-l6ccFqedPdu984.crv", "Ed25519"),
+				),
+			},
+			// Keys that cannot be converted and duplicate key IDs are skipped
+			{
+				Config: `
+					resource "jose_jwk" "ed25519" {
+						kid        = "this-is-a-key-id-for-eddsa-key"
+						public_key = file("./fixtures/ed25519-pub.pem")
+					}
+
+					data "jose_jwk_pem" "jwks" {
+						jwks = jsonencode({
+							keys = [
+								{ kty = "oct", kid = "this-is-a-key-id-for-hmac-key", k = "c2VjcmV0" },
+								{ kty = "unknown", kid = "this-is-a-key-id-for-unknown-key" },
+								jsondecode(jose_jwk.ed25519.jwk),
+								merge(jsondecode(jose_jwk.ed25519.jwk), { use = "enc" }),
+							]
+						})
+					}
+				`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.jose_jwk_pem.jwks", "keys.%", "1"),
+					resource.TestCheckResourceAttr("data.jose_jwk_pem.jwks", "keys.this-is-a-key-id-for-eddsa-key.kty", "OKP"),
+					resource.TestCheckResourceAttr("data.jose_jwk_pem.jwks", "keys.this-is-a-key-id-for-eddsa-key.use", "sig"),
+				),
+			},
+		},
+	})
+}
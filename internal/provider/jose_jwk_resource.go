@@ -6,8 +6,12 @@ package provider
 
 import (
 	"context"
-	"encoding/base64"
+	"crypto"
+	"fmt"
+	"maps"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -20,18 +24,38 @@ type joseJwkResource struct{}
 
 // jwtResourceModel describes the resource data model.
 type joseJwkResourceModel struct {
-	PublicKey types.String `tfsdk:"public_key"`
-	Alg       types.String `tfsdk:"alg"`
-	KID       types.String `tfsdk:"kid"`
-	Use       types.String `tfsdk:"use"`
-	JWK       types.String `tfsdk:"jwk"`
-	JWKBase64 types.String `tfsdk:"jwk_b64"`
+	PublicKey   types.String `tfsdk:"public_key"`
+	PrivateKey  types.String `tfsdk:"private_key"`
+	Certificate types.String `tfsdk:"certificate"`
+
+	TrustAnchors            types.String `tfsdk:"trust_anchors"`
+	CertificateExpiryWindow types.String `tfsdk:"certificate_expiry_window"`
+	CertificateValidation   types.String `tfsdk:"certificate_validation"`
+	Alg                     types.String `tfsdk:"alg"`
+	KID                     types.String `tfsdk:"kid"`
+	Use                     types.String `tfsdk:"use"`
+	KeyOps                  types.Set    `tfsdk:"key_ops"`
+	JWK                     types.String `tfsdk:"jwk"`
+	JWKBase64               types.String `tfsdk:"jwk_b64"`
+	PrivateJWK              types.String `tfsdk:"private_jwk"`
+
+	ThumbprintSHA256 types.String `tfsdk:"thumbprint_sha256"`
+	ThumbprintSHA1   types.String `tfsdk:"thumbprint_sha1"`
+	ThumbprintURI    types.String `tfsdk:"thumbprint_uri"`
+}
+
+// joseJwkKeepersResourceModel describes the jose_jwk data model, with keepers
+// in addition to the JWK attributes shared with jose_jwks.
+type joseJwkKeepersResourceModel struct {
+	joseJwkResourceModel
+	Keepers types.Map `tfsdk:"keepers"`
 }
 
 // Ensure provider defined types fully satisfy framework interfaces.
 var (
-	_ resource.Resource                = &joseJwkResource{}
-	_ resource.ResourceWithImportState = &joseJwkResource{}
+	_ resource.Resource                   = &joseJwkResource{}
+	_ resource.ResourceWithImportState    = &joseJwkResource{}
+	_ resource.ResourceWithValidateConfig = &joseJwkResource{}
 )
 
 func NewJoseJwkResource() resource.Resource {
@@ -43,9 +67,134 @@ func (r *joseJwkResource) Metadata(ctx context.Context, req resource.MetadataReq
 }
 
 func (r *joseJwkResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	attributes := maps.Clone(jwkSchema)
+	attributes["keepers"] = keepersAttribute
+
 	resp.Schema = schema.Schema{
-		MarkdownDescription: "Creates a JWK from a public key.",
-		Attributes:          jwkSchema,
+		MarkdownDescription: "Creates a JWK from a public key or an X.509 certificate chain, or a public and private JWK from a private key.",
+		Attributes:          attributes,
+	}
+}
+
+func (r *joseJwkResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data joseJwkKeepersResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	validateJWKKeyOps(data.joseJwkResourceModel, path.Empty(), &resp.Diagnostics)
+	validateJWKAlgorithm(data.joseJwkResourceModel, path.Empty(), &resp.Diagnostics)
+	validateJWKCertificate(data.joseJwkResourceModel, path.Empty(), &resp.Diagnostics)
+}
+
+// Validate the key operations of a JWK against its use.  Key operations for
+// both signing and encryption are reported as a warning, as they should not
+// be combined (RFC 7517 section 4.3).  Unknown values are not validated.
+func validateJWKKeyOps(data joseJwkResourceModel, p path.Path, diags *diag.Diagnostics) {
+	if data.KeyOps.IsNull() || data.KeyOps.IsUnknown() || data.Use.IsUnknown() {
+		return
+	}
+
+	keyOps := jwkKeyOps(data.KeyOps)
+	use, err := jwkKeyOpsUse(data.Use.ValueString(), keyOps)
+	if err != nil {
+		diags.AddAttributeError(p.AtName("key_ops"), "Invalid key operations", err.Error())
+		return
+	}
+	if use == "" {
+		diags.AddAttributeWarning(p.AtName("key_ops"), "Unrelated key operations", fmt.Sprintf("key_ops %v combines signing and encryption operations, the same key should not be used for both", keyOps))
+	}
+}
+
+// Validate the algorithm of a JWK against its use or key operations and, when
+// the key is known, its key type.  Unknown values are not validated.
+func validateJWKAlgorithm(data joseJwkResourceModel, p path.Path, diags *diag.Diagnostics) {
+	if data.Alg.IsNull() || data.Alg.IsUnknown() || data.Use.IsUnknown() || data.KeyOps.IsUnknown() {
+		return
+	}
+
+	// Contradicting key operations are reported by validateJWKKeyOps.
+	use, err := jwkKeyOpsUse(data.Use.ValueString(), jwkKeyOps(data.KeyOps))
+	if err != nil {
+		return
+	}
+	pubKey := configuredPublicKey(data)
+	if pubKey == nil {
+		return
+	}
+	if _, err := jwkAlgorithm(pubKey, data.Alg.ValueString(), use); err != nil {
+		diags.AddAttributeError(p.AtName("alg"), "Invalid algorithm", err.Error())
+	}
+}
+
+// Return the public key of a JWK from its private or public key, or nil when
+// the key is not set, unknown or invalid.  The key is parsed again at apply
+// time, parse errors are reported then.
+func configuredPublicKey(data joseJwkResourceModel) crypto.PublicKey {
+	if data.PrivateKey.ValueString() != "" {
+		if privateKey, err := parsePrivateKey([]byte(data.PrivateKey.ValueString()), data.Alg); err == nil {
+			if signer, ok := privateKey.cryptoKey().(crypto.Signer); ok {
+				return signer.Public()
+			}
+		}
+	} else if data.PublicKey.ValueString() != "" {
+		if pubKey, err := parsePublicKey([]byte(data.PublicKey.ValueString())); err == nil {
+			return pubKey
+		}
+	} else if data.Certificate.ValueString() != "" {
+		if certs, err := parseCertificateChain([]byte(data.Certificate.ValueString())); err == nil {
+			return certificatePublicKey(certs[0])
+		}
+	}
+
+	return nil
+}
+
+// Validate the certificate chain of a JWK against its key, its trust anchors
+// and its expiry window.  Unknown values are not validated.
+func validateJWKCertificate(data joseJwkResourceModel, p path.Path, diags *diag.Diagnostics) {
+	var (
+		pubKey crypto.PublicKey
+		opts   = certificateValidationOptions{Now: time.Now()}
+	)
+
+	if data.Certificate.IsNull() || data.Certificate.IsUnknown() || data.TrustAnchors.IsUnknown() || data.CertificateExpiryWindow.IsUnknown() {
+		return
+	}
+
+	certs, err := parseCertificateChain([]byte(data.Certificate.ValueString()))
+	if err != nil {
+		diags.AddAttributeError(p.AtName("certificate"), "Invalid certificate", err.Error())
+		return
+	}
+
+	if data.PrivateKey.ValueString() != "" || data.PublicKey.ValueString() != "" {
+		pubKey = configuredPublicKey(data)
+	}
+	if pubKey != nil && !publicKeysEqual(pubKey, certificatePublicKey(certs[0])) {
+		diags.AddAttributeError(p.AtName("certificate"), "Invalid certificate", "the leaf certificate does not match the key")
+	}
+
+	if data.TrustAnchors.ValueString() != "" {
+		opts.TrustAnchors, err = parseCertificateChain([]byte(data.TrustAnchors.ValueString()))
+		if err != nil {
+			diags.AddAttributeError(p.AtName("trust_anchors"), "Invalid trust anchors", err.Error())
+			return
+		}
+	}
+	if data.CertificateExpiryWindow.ValueString() != "" {
+		opts.ExpiryWindow, _ = time.ParseDuration(data.CertificateExpiryWindow.ValueString())
+	}
+
+	for _, problem := range validateCertificateChain(certs, opts) {
+		if data.CertificateValidation.ValueString() == "warning" {
+			diags.AddAttributeWarning(p.AtName("certificate"), "Invalid certificate chain", problem.Error())
+		} else {
+			diags.AddAttributeError(p.AtName("certificate"), "Invalid certificate chain", problem.Error())
+		}
 	}
 }
 
@@ -53,7 +202,7 @@ func (r *joseJwkResource) Configure(ctx context.Context, req resource.ConfigureR
 }
 
 func (r *joseJwkResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
-	var data joseJwkResourceModel
+	var data joseJwkKeepersResourceModel
 
 	// Read plan data into the model
 	// Now the model '&data' holds the plan data
@@ -63,17 +212,12 @@ func (r *joseJwkResource) Create(ctx context.Context, req resource.CreateRequest
 		return
 	}
 
-	jwkJSON, err := createJWK(data)
-	if err != nil {
+	// Save the JWK, encoded in Base64 as well, and its thumbprints
+	if err := createJWK(&data.joseJwkResourceModel); err != nil {
 		resp.Diagnostics.AddError("Error creating JWK", err.Error())
 		return
 	}
 
-	data.JWK = types.StringValue(string(jwkJSON))
-
-	// Save jwkJSON as data.JWKBase64 encoded in Base64
-	data.JWKBase64 = types.StringValue(base64.StdEncoding.EncodeToString(jwkJSON))
-
 	// Write logs using the tflog package
 	// Documentation: https://terraform.io/plugin/log
 	tflog.Trace(ctx, "created a resource")
@@ -83,7 +227,7 @@ func (r *joseJwkResource) Create(ctx context.Context, req resource.CreateRequest
 }
 
 func (r *joseJwkResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
-	var data joseJwkResourceModel
+	var data joseJwkKeepersResourceModel
 
 	// Read Terraform prior state data into the model
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
@@ -92,11 +236,20 @@ func (r *joseJwkResource) Read(ctx context.Context, req resource.ReadRequest, re
 		return
 	}
 
+	// JWKs created by earlier versions have the "RS256" algorithm whatever
+	// their key type, and no thumbprints.
+	if err := updateJWKAlgorithm(&data.joseJwkResourceModel); err != nil {
+		resp.Diagnostics.AddWarning("Failed to update the JWK algorithm", err.Error())
+	}
+	if err := updateJWKThumbprints(&data.joseJwkResourceModel); err != nil {
+		resp.Diagnostics.AddWarning("Failed to compute the JWK thumbprints", err.Error())
+	}
+
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
 func (r *joseJwkResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	var data joseJwkResourceModel
+	var data joseJwkKeepersResourceModel
 
 	// Read Terraform plan data into the model
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
@@ -105,11 +258,19 @@ func (r *joseJwkResource) Update(ctx context.Context, req resource.UpdateRequest
 		return
 	}
 
+	// Only the settings checked during plan can be updated in place, which
+	// leave the JWK unchanged.  It is created again for the attributes missing
+	// from the prior state.
+	if err := createJWK(&data.joseJwkResourceModel); err != nil {
+		resp.Diagnostics.AddError("Error creating JWK", err.Error())
+		return
+	}
+
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
 func (r *joseJwkResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
-	var data joseJwkResourceModel
+	var data joseJwkKeepersResourceModel
 
 	// Read Terraform prior state data into the model
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
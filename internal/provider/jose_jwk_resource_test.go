@@ -5,11 +5,13 @@
 package provider
 
 import (
+	"regexp"
 	"strings"
 	"testing"
 
 	"github.com/aiyor-tf/terraform-provider-jose/internal/provider/fixtures"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
 )
 
 func TestAccJoseJwkResource(t *testing.T) {
@@ -31,6 +33,229 @@ func TestAccJoseJwkResource(t *testing.T) {
 					resource.TestCheckResourceAttr("jose_jwk.test", "jwk_b64", strings.TrimSpace(fixtures.B64JWKRSA)),
 				),
 			},
+			// Private JWK export, with the public JWK derived from the same key
+			{
+				Config: `
+					resource "jose_jwk" "test" {
+						kid         = "this-is-a-key-id-for-ecdsa-key"
+						private_key = file("./fixtures/ecdsa.pem")
+					}
+
+					resource "jose_jwk" "public" {
+						kid        = "this-is-a-key-id-for-ecdsa-key"
+						public_key = file("./fixtures/ecdsa-pub.pem")
+					}
+				`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair("jose_jwk.test", "jwk", "jose_jwk.public", "jwk"),
+					resource.TestMatchResourceAttr("jose_jwk.test", "private_jwk", regexp.MustCompile(`"d":"[\w-]+"`)),
+					resource.TestCheckNoResourceAttr("jose_jwk.public", "private_jwk"),
+				),
+			},
+			// RFC 7638 thumbprints, with the key ID derived from the thumbprint
+			{
+				Config: `
+					resource "jose_jwk" "test" {
+						public_key = file("./fixtures/ecdsa-pub.pem")
+					}
+				`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("jose_jwk.test", "kid", "FJFJu9jb7A7ogjx3uryvwHPzRQgegJTZihI2sKmyKCY"),
+					resource.TestCheckResourceAttr("jose_jwk.test", "thumbprint_sha256", "FJFJu9jb7A7ogjx3uryvwHPzRQgegJTZihI2sKmyKCY"),
+					resource.TestCheckResourceAttr("jose_jwk.test", "thumbprint_sha1", "tpfG7nIVSDQh11q4Zu97rJn6bFY"),
+					resource.TestCheckResourceAttr("jose_jwk.test", "thumbprint_uri", "urn:ietf:params:oauth:jwk-thumbprint:sha-256:FJFJu9jb7A7ogjx3uryvwHPzRQgegJTZihI2sKmyKCY"),
+				),
+			},
+			// X.509 certificate chain, with the key taken from the leaf
+			{
+				Config: `
+					resource "jose_jwk" "test" {
+						certificate = file("./fixtures/ecdsa-cert.pem")
+					}
+				`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("jose_jwk.test", "kid", "FJFJu9jb7A7ogjx3uryvwHPzRQgegJTZihI2sKmyKCY"),
+					resource.TestMatchResourceAttr("jose_jwk.test", "jwk", regexp.MustCompile(`"x5c":\["[^"]+","[^"]+"\]`)),
+					resource.TestMatchResourceAttr("jose_jwk.test", "jwk", regexp.MustCompile(`"x5t#S256":"I3sWfVkINviZfg13uWyGsxiNGVsV-yfxhT6ZllwyxL4"`)),
+				),
+			},
+			// Certificate chain building to a trust anchor
+			{
+				Config: `
+					resource "jose_jwk" "test" {
+						certificate               = file("./fixtures/ecdsa-cert.pem")
+						private_key               = file("./fixtures/ecdsa.pem")
+						trust_anchors             = file("./fixtures/ca.pem")
+						certificate_expiry_window = "720h"
+					}
+				`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("jose_jwk.test", "kid", "FJFJu9jb7A7ogjx3uryvwHPzRQgegJTZihI2sKmyKCY"),
+				),
+			},
+			// The settings checked during plan are updated in place
+			{
+				Config: `
+					resource "jose_jwk" "test" {
+						certificate               = file("./fixtures/ecdsa-cert.pem")
+						private_key               = file("./fixtures/ecdsa.pem")
+						trust_anchors             = file("./fixtures/ca.pem")
+						certificate_expiry_window = "24h"
+						certificate_validation    = "warning"
+					}
+				`,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("jose_jwk.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("jose_jwk.test", "kid", "FJFJu9jb7A7ogjx3uryvwHPzRQgegJTZihI2sKmyKCY"),
+					resource.TestCheckResourceAttr("jose_jwk.test", "certificate_validation", "warning"),
+				),
+			},
+			// Encryption key, with the key management algorithm defaulted
+			{
+				Config: `
+					resource "jose_jwk" "test" {
+						public_key = file("./fixtures/ecdsa-pub.pem")
+						use        = "enc"
+					}
+				`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("jose_jwk.test", "alg", "ECDH-ES+A256KW"),
+					resource.TestMatchResourceAttr("jose_jwk.test", "jwk", regexp.MustCompile(`"use":"enc"`)),
+				),
+			},
+			// Signing algorithm on an encryption key
+			{
+				Config: `
+					resource "jose_jwk" "test" {
+						public_key = file("./fixtures/rsa-pub.pem")
+						alg        = "RS256"
+						use        = "enc"
+					}
+				`,
+				ExpectError: regexp.MustCompile(`signing algorithm "RS256" cannot be used with an encryption key`),
+			},
+			// Key operations instead of use
+			{
+				Config: `
+					resource "jose_jwk" "test" {
+						public_key = file("./fixtures/rsa-pub.pem")
+						key_ops    = ["wrapKey", "encrypt"]
+					}
+				`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("jose_jwk.test", "alg", "RSA-OAEP-256"),
+					resource.TestCheckNoResourceAttr("jose_jwk.test", "use"),
+					resource.TestMatchResourceAttr("jose_jwk.test", "jwk", regexp.MustCompile(`^\{"key_ops":\["encrypt","wrapKey"\],"kty":"RSA",`)),
+				),
+			},
+			// Key operations contradicting use
+			{
+				Config: `
+					resource "jose_jwk" "test" {
+						public_key = file("./fixtures/rsa-pub.pem")
+						use        = "sig"
+						key_ops    = ["decrypt"]
+					}
+				`,
+				ExpectError: regexp.MustCompile(`contradict use = "sig"`),
+			},
+			// secp256k1 key, not to be confused with P-256
+			{
+				Config: `
+					resource "jose_jwk" "test" {
+						public_key = file("./fixtures/secp256k1-pub.pem")
+					}
+				`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("jose_jwk.test", "alg", "ES256K"),
+					resource.TestCheckResourceAttr("jose_jwk.test", "kid", "KfpYkyW9CTL3aVUxgs3ZrkiQsMZzY-Iwa2jzJjNPt5o"),
+					resource.TestMatchResourceAttr("jose_jwk.test", "jwk", regexp.MustCompile(`"kty":"EC",.*"crv":"secp256k1",.*"x":"UWweMCZNu21QPILwaURRMVwRPcOd5cECfzPLSdTtsbs","y":"upcLDX0ALgQ5qpI0BlLjUQe0eeXjC-9dTHOP2L0AcVA"`)),
+				),
+			},
+			{
+				Config: `
+					resource "jose_jwk" "test" {
+						public_key = file("./fixtures/secp256k1-pub.pem")
+						alg        = "ES256"
+					}
+				`,
+				ExpectError: regexp.MustCompile(`signing algorithm "ES256" cannot be used with this key type`),
+			},
+			// X25519 key, usable for encryption only
+			{
+				Config: `
+					resource "jose_jwk" "test" {
+						public_key = file("./fixtures/x25519-pub.pem")
+					}
+				`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("jose_jwk.test", "alg", "ECDH-ES+A256KW"),
+					resource.TestCheckResourceAttr("jose_jwk.test", "use", "enc"),
+					resource.TestCheckResourceAttr("jose_jwk.test", "kid", "byy2TIjzKFBWE7RwacdTzTfUB1e6Mu9_9XOjP4d1Gpc"),
+					resource.TestMatchResourceAttr("jose_jwk.test", "jwk", regexp.MustCompile(`"kty":"OKP",.*"crv":"X25519",.*"x":"s9ZyNZJJQ0VLt2_eGMQMjEXVfHjdjo7XcwPgRD7vF24"`)),
+				),
+			},
+			// X448 key used for signing
+			{
+				Config: `
+					resource "jose_jwk" "test" {
+						public_key = file("./fixtures/x448-pub.pem")
+						use        = "sig"
+					}
+				`,
+				ExpectError: regexp.MustCompile(`X448 keys can only be used for encryption`),
+			},
+			// Keepers force the replacement of the JWK and the JWK Set
+			{
+				Config: `
+					resource "jose_jwk" "test" {
+						public_key = file("./fixtures/ecdsa-pub.pem")
+						keepers    = { rotation = "2026-01" }
+					}
+
+					resource "jose_jwks" "test" {
+						jwks_properties = [{ public_key = file("./fixtures/rsa-pub.pem") }]
+						keepers         = { rotation = "2026-01" }
+					}
+				`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("jose_jwk.test", "keepers.rotation", "2026-01"),
+					resource.TestCheckResourceAttr("jose_jwks.test", "keepers.rotation", "2026-01"),
+				),
+			},
+			{
+				Config: `
+					resource "jose_jwk" "test" {
+						public_key = file("./fixtures/ecdsa-pub.pem")
+						keepers    = { rotation = "2026-07" }
+					}
+
+					resource "jose_jwks" "test" {
+						jwks_properties = [{ public_key = file("./fixtures/rsa-pub.pem") }]
+						keepers         = { rotation = "2026-07" }
+					}
+				`,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("jose_jwk.test", plancheck.ResourceActionReplace),
+						plancheck.ExpectResourceAction("jose_jwks.test", plancheck.ResourceActionReplace),
+					},
+				},
+			},
+			// Certificate expiring within the window
+			{
+				Config: `
+					resource "jose_jwk" "test" {
+						certificate               = file("./fixtures/ecdsa-cert.pem")
+						certificate_expiry_window = "1752000h"
+					}
+				`,
+				ExpectError: regexp.MustCompile(`expires at .*, within 1752000h`),
+			},
 			// Update and Read testing
 			// {
 			// 	Config: testAccJoseJwkResourceConfig("two"),
@@ -42,3 +267,47 @@ func TestAccJoseJwkResource(t *testing.T) {
 		},
 	})
 }
+
+// JWKs created by 0.1.0 have the "RS256" algorithm whatever their key type.
+func TestAccJoseJwkResourceUpgrade(t *testing.T) {
+	config := `
+		resource "jose_jwk" "test" {
+			kid        = "this-is-a-key-id-for-ecdsa-key"
+			public_key = file("./fixtures/ecdsa-pub.pem")
+			use        = "sig"
+		}
+	`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() { testAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				ExternalProviders: map[string]resource.ExternalProvider{
+					"jose": {
+						Source:            "aiyor-tf/jose",
+						VersionConstraint: "0.1.0",
+					},
+				},
+				Config: config,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("jose_jwk.test", "alg", "RS256"),
+				),
+			},
+			{
+				ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+				Config: strings.Replace(config, `use        = "sig"`, `use        = "sig"
+			certificate_validation = "warning"`, 1),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("jose_jwk.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("jose_jwk.test", "alg", "ES256"),
+					resource.TestMatchResourceAttr("jose_jwk.test", "jwk", regexp.MustCompile(`"alg":"ES256"`)),
+					resource.TestCheckResourceAttr("jose_jwk.test", "thumbprint_sha256", "FJFJu9jb7A7ogjx3uryvwHPzRQgegJTZihI2sKmyKCY"),
+				),
+			},
+		},
+	})
+}
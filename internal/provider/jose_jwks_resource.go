@@ -8,11 +8,15 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"fmt"
 
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
@@ -22,6 +26,7 @@ type joseJwksResource struct{}
 // jwtResourceModel describes the resource data model.
 type joseJwksResourceModel struct {
 	JWKSProperties []joseJwkResourceModel `tfsdk:"jwks_properties"`
+	Keepers        types.Map              `tfsdk:"keepers"`
 	JWKS           types.String           `tfsdk:"jwks"`
 	JWKSBase64     types.String           `tfsdk:"jwks_b64"`
 }
@@ -36,10 +41,41 @@ type JWKSet struct {
 	Keys []json.RawMessage `json:"keys"` // Store keys as raw JSON
 }
 
+// Create the JWK of each key and save the resulting JWK Set into the computed
+// attributes of the model.
+func createJWKS(data *joseJwksResourceModel) error {
+	// Create a JWKSet to store all keys
+	jwkSet := JWKSet{Keys: make([]json.RawMessage, 0)}
+
+	for i := range data.JWKSProperties {
+		if err := createJWK(&data.JWKSProperties[i]); err != nil {
+			return err
+		}
+
+		// Append the raw JSON to the JWKSet.Keys
+		jwkSet.Keys = append(jwkSet.Keys, json.RawMessage(data.JWKSProperties[i].JWK.ValueString()))
+	}
+
+	// Marshal the JWKSet to JSON
+	jwkSetJSON, err := json.Marshal(jwkSet)
+	if err != nil {
+		return fmt.Errorf("marshalling JWK Set to JSON: %w", err)
+	}
+
+	// Save the JWKSet result into JWKS
+	data.JWKS = types.StringValue(string(jwkSetJSON))
+
+	// Save jwkJSON as data.JWKBase64 encoded in Base64
+	data.JWKSBase64 = types.StringValue(base64.StdEncoding.EncodeToString(jwkSetJSON))
+
+	return nil
+}
+
 // Ensure provider defined types fully satisfy framework interfaces.
 var (
-	_ resource.Resource                = &joseJwksResource{}
-	_ resource.ResourceWithImportState = &joseJwksResource{}
+	_ resource.Resource                   = &joseJwksResource{}
+	_ resource.ResourceWithImportState    = &joseJwksResource{}
+	_ resource.ResourceWithValidateConfig = &joseJwksResource{}
 )
 
 func NewJoseJwksResource() resource.Resource {
@@ -62,27 +98,59 @@ func (r *joseJwksResource) Schema(ctx context.Context, req resource.SchemaReques
 				MarkdownDescription: "JWK Set configurable attribute.",
 				Required:            true,
 			},
+			"keepers": keepersAttribute,
 			"jwks": schema.StringAttribute{
 				Computed:    true,
 				Description: "The resulting JWK Set in JSON format.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
 			},
 			"jwks_b64": schema.StringAttribute{
 				Computed:    true,
 				Description: "The resulting JWK Set encoded in base64.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
 			},
 		},
 	}
 }
 
+func (r *joseJwksResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var properties types.Set
+
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("jwks_properties"), &properties)...)
+
+	if resp.Diagnostics.HasError() || properties.IsNull() || properties.IsUnknown() {
+		return
+	}
+
+	// Each JWK is validated as done by jose_jwk
+	for _, element := range properties.Elements() {
+		var item joseJwkResourceModel
+
+		object, ok := element.(types.Object)
+		if !ok || object.IsUnknown() {
+			continue
+		}
+		resp.Diagnostics.Append(object.As(ctx, &item, basetypes.ObjectAsOptions{})...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		validateJWKKeyOps(item, path.Root("jwks_properties").AtSetValue(element), &resp.Diagnostics)
+		validateJWKAlgorithm(item, path.Root("jwks_properties").AtSetValue(element), &resp.Diagnostics)
+		validateJWKCertificate(item, path.Root("jwks_properties").AtSetValue(element), &resp.Diagnostics)
+	}
+}
+
 func (r *joseJwksResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 }
 
 func (r *joseJwksResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var data joseJwksResourceModel
 
-	// Create a JWKSet to store all keys
-	jwkSet := JWKSet{Keys: make([]json.RawMessage, 0)}
-
 	// Read plan data into the model
 	// Now the model '&data' holds the plan data
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
@@ -91,32 +159,11 @@ func (r *joseJwksResource) Create(ctx context.Context, req resource.CreateReques
 		return
 	}
 
-	//for _, item := range data.JWKSProperties {}
-	for _, item := range data.JWKSProperties {
-		jwkJSON, err := createJWK(item)
-
-		if err != nil {
-			resp.Diagnostics.AddError("Error creating JWK:", err.Error())
-			return
-		}
-
-		// Append the raw JSON to the JWKSet.Keys
-		jwkSet.Keys = append(jwkSet.Keys, jwkJSON)
-	}
-
-	// Marshal the JWKSet to JSON
-	jwkSetJSON, err := json.Marshal(jwkSet)
-	if err != nil {
-		resp.Diagnostics.AddError("Error marshalling JWK Set to JSON:", err.Error())
+	if err := createJWKS(&data); err != nil {
+		resp.Diagnostics.AddError("Error creating JWK Set", err.Error())
 		return
 	}
 
-	// Save the JWKSet result into JWKS
-	data.JWKS = types.StringValue(string(jwkSetJSON))
-
-	// Save jwkJSON as data.JWKBase64 encoded in Base64
-	data.JWKSBase64 = types.StringValue(base64.StdEncoding.EncodeToString(jwkSetJSON))
-
 	// Write logs using the tflog package
 	// Documentation: https://terraform.io/plugin/log
 	tflog.Trace(ctx, "created a resource")
@@ -135,6 +182,26 @@ func (r *joseJwksResource) Read(ctx context.Context, req resource.ReadRequest, r
 		return
 	}
 
+	// JWKs created by earlier versions have the "RS256" algorithm whatever
+	// their key type, and no thumbprints.
+	updated := false
+	for i := range data.JWKSProperties {
+		alg := data.JWKSProperties[i].Alg
+		if err := updateJWKAlgorithm(&data.JWKSProperties[i]); err != nil {
+			resp.Diagnostics.AddWarning("Failed to update the JWK algorithm", err.Error())
+		}
+		updated = updated || !data.JWKSProperties[i].Alg.Equal(alg)
+		if err := updateJWKThumbprints(&data.JWKSProperties[i]); err != nil {
+			resp.Diagnostics.AddWarning("Failed to compute the JWK thumbprints", err.Error())
+		}
+	}
+	// The JWK Set holds the updated JWKs.
+	if updated {
+		if err := createJWKS(&data); err != nil {
+			resp.Diagnostics.AddWarning("Failed to update the JWK Set", err.Error())
+		}
+	}
+
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
@@ -148,6 +215,14 @@ func (r *joseJwksResource) Update(ctx context.Context, req resource.UpdateReques
 		return
 	}
 
+	// Only the settings checked during plan can be updated in place, which
+	// leave the JWKs unchanged.  They are created again for the attributes
+	// missing from the prior state.
+	if err := createJWKS(&data); err != nil {
+		resp.Diagnostics.AddError("Error creating JWK Set", err.Error())
+		return
+	}
+
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
@@ -0,0 +1,258 @@
+// Copyright (c) HashiCorp, Inc.
+// Copyright (c) Tze Liang
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var (
+	_ resource.Resource                     = &joseJwsSignResource{}
+	_ resource.ResourceWithConfigValidators = &joseJwsSignResource{}
+)
+
+func NewJoseJwsSignResource() resource.Resource {
+	return &joseJwsSignResource{}
+}
+
+// joseJwsSignResource defines the resource implementation.
+type joseJwsSignResource struct{}
+
+// joseJwsSignResourceModel describes the resource data model.
+type joseJwsSignResourceModel struct {
+	Payload       types.String            `tfsdk:"payload"`
+	PayloadBase64 types.String            `tfsdk:"payload_base64"`
+	PrivateKey    types.String            `tfsdk:"private_key"`
+	Alg           types.String            `tfsdk:"alg"`
+	KID           types.String            `tfsdk:"kid"`
+	Signatures    []joseJwsSignatureModel `tfsdk:"signatures"`
+	Serialization types.String            `tfsdk:"serialization"`
+	Detached      types.Bool              `tfsdk:"detached"`
+	Unencoded     types.Bool              `tfsdk:"unencoded_payload"`
+	Typ           types.String            `tfsdk:"typ"`
+	CTY           types.String            `tfsdk:"cty"`
+	JWS           types.String            `tfsdk:"jws"`
+}
+
+// joseJwsSignatureModel describes a signature in the signatures attribute.
+type joseJwsSignatureModel struct {
+	PrivateKey             types.String `tfsdk:"private_key"`
+	Alg                    types.String `tfsdk:"alg"`
+	KID                    types.String `tfsdk:"kid"`
+	ProtectedHeadersJSON   types.String `tfsdk:"protected_headers_json"`
+	UnprotectedHeadersJSON types.String `tfsdk:"unprotected_headers_json"`
+}
+
+func (r *joseJwsSignResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_jws_sign"
+}
+
+func (r *joseJwsSignResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Creates a JWS over an arbitrary payload, signed by one or more keys. Supports RSA, ECDSA and EdDSA keys, in compact, general JSON or flattened JSON serialization.",
+		Attributes:          jwsSchema,
+	}
+}
+
+func (r *joseJwsSignResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		resourcevalidator.ExactlyOneOf(
+			path.MatchRoot("payload"),
+			path.MatchRoot("payload_base64"),
+		),
+		resourcevalidator.ExactlyOneOf(
+			path.MatchRoot("private_key"),
+			path.MatchRoot("signatures"),
+		),
+		resourcevalidator.Conflicting(
+			path.MatchRoot("signatures"),
+			path.MatchRoot("alg"),
+		),
+		resourcevalidator.Conflicting(
+			path.MatchRoot("signatures"),
+			path.MatchRoot("kid"),
+		),
+	}
+}
+
+func (r *joseJwsSignResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+}
+
+func (r *joseJwsSignResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var (
+		data    joseJwsSignResourceModel
+		payload []byte
+		err     error
+	)
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !data.PayloadBase64.IsNull() {
+		payload, err = base64.StdEncoding.DecodeString(data.PayloadBase64.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Invalid base64 payload", err.Error())
+			return
+		}
+	} else {
+		payload = []byte(data.Payload.ValueString())
+	}
+
+	// Headers shared by every signature
+	shared := map[string]interface{}{}
+	if data.Typ.ValueString() != "" {
+		shared["typ"] = data.Typ.ValueString()
+	}
+	if data.CTY.ValueString() != "" {
+		shared["cty"] = data.CTY.ValueString()
+	}
+
+	var signers []jwsSigner
+	if !data.PrivateKey.IsNull() {
+		// Parse PEM into correct private key type
+		privateKey, err := parsePrivateKey([]byte(data.PrivateKey.ValueString()), data.Alg)
+		if err != nil {
+			resp.Diagnostics.AddError("Invalid private key", err.Error())
+			return
+		}
+		ignored, err := checkPrivateKeyAlgorithm(privateKey, data.Alg)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("alg"), "Invalid algorithm", err.Error())
+			return
+		}
+		if ignored {
+			resp.Diagnostics.AddAttributeWarning(path.Root("alg"), "Ignored algorithm", fmt.Sprintf("algorithm %q only applies to RSA keys, the payload is signed with %q", data.Alg.ValueString(), privateKey.algorithm()))
+		}
+		if data.Alg.IsUnknown() || data.Alg.IsNull() {
+			data.Alg = types.StringValue(privateKey.algorithm())
+		}
+
+		protected := map[string]interface{}{}
+		if data.KID.ValueString() != "" {
+			protected["kid"] = data.KID.ValueString()
+		}
+		signers = append(signers, jwsSigner{key: privateKey, protected: protected})
+	}
+
+	for i, item := range data.Signatures {
+		privateKey, err := parsePrivateKey([]byte(item.PrivateKey.ValueString()), item.Alg)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("signatures").AtListIndex(i).AtName("private_key"), "Invalid private key", err.Error())
+			return
+		}
+		ignored, err := checkPrivateKeyAlgorithm(privateKey, item.Alg)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("signatures").AtListIndex(i).AtName("alg"), "Invalid algorithm", err.Error())
+			return
+		}
+		if ignored {
+			resp.Diagnostics.AddAttributeWarning(path.Root("signatures").AtListIndex(i).AtName("alg"), "Ignored algorithm", fmt.Sprintf("algorithm %q only applies to RSA keys, the signature is created with %q", item.Alg.ValueString(), privateKey.algorithm()))
+		}
+		if item.Alg.IsUnknown() || item.Alg.IsNull() {
+			data.Signatures[i].Alg = types.StringValue(privateKey.algorithm())
+		}
+
+		protected, err := parseJWSHeaders(item.ProtectedHeadersJSON.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("signatures").AtListIndex(i).AtName("protected_headers_json"), "Invalid protected headers JSON", err.Error())
+			return
+		}
+		if item.KID.ValueString() != "" {
+			protected["kid"] = item.KID.ValueString()
+		}
+
+		unprotected, err := parseJWSHeaders(item.UnprotectedHeadersJSON.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("signatures").AtListIndex(i).AtName("unprotected_headers_json"), "Invalid unprotected headers JSON", err.Error())
+			return
+		}
+
+		signers = append(signers, jwsSigner{key: privateKey, protected: protected, unprotected: unprotected})
+	}
+
+	for _, signer := range signers {
+		for name, value := range shared {
+			if _, ok := signer.protected[name]; !ok {
+				signer.protected[name] = value
+			}
+		}
+	}
+
+	jws, err := signJWS(payload, signers, jwsOptions{
+		Serialization: data.Serialization.ValueString(),
+		Detached:      data.Detached.ValueBool(),
+		Unencoded:     data.Unencoded.ValueBool(),
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to sign JWS", err.Error())
+		return
+	}
+
+	if data.Alg.IsUnknown() {
+		data.Alg = types.StringNull()
+	}
+	data.JWS = types.StringValue(jws)
+
+	// Write logs using the tflog package
+	// Documentation: https://terraform.io/plugin/log
+	tflog.Trace(ctx, "created a resource")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *joseJwsSignResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data joseJwsSignResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *joseJwsSignResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data joseJwsSignResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *joseJwsSignResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data joseJwsSignResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
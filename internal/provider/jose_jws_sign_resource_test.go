@@ -0,0 +1,111 @@
+// Copyright (c) HashiCorp, Inc.
+// Copyright (c) Tze Liang
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccJoseJwsSignResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: `
+					resource "jose_jws_sign" "test" {
+						payload     = "this-is-a-document"
+						private_key = file("./fixtures/ecdsa.pem")
+						kid         = "this-is-a-key-id-for-ecdsa-key"
+						typ         = "text/plain"
+					}
+
+					data "jose_jwt_decode" "test" {
+						jwt = jose_jws_sign.test.jws
+					}
+				`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestMatchResourceAttr("jose_jws_sign.test", "jws", regexp.MustCompile(`^[\w-]+\.[\w-]+\.[\w-]+$`)),
+					resource.TestCheckResourceAttr("jose_jws_sign.test", "alg", "ES256"),
+					resource.TestCheckResourceAttr("data.jose_jwt_decode.test", "alg", "ES256"),
+					resource.TestCheckResourceAttr("data.jose_jwt_decode.test", "kid", "this-is-a-key-id-for-ecdsa-key"),
+				),
+			},
+			// Multiple signatures in general JSON serialization
+			{
+				Config: `
+					resource "jose_jws_sign" "test" {
+						payload       = "this-is-a-document"
+						serialization = "json"
+						signatures = [
+							{
+								private_key = file("./fixtures/ecdsa.pem")
+								kid         = "this-is-the-old-key-id"
+							},
+							{
+								private_key              = file("./fixtures/ed25519.pem")
+								kid                      = "this-is-the-new-key-id"
+								unprotected_headers_json = jsonencode({ "rotation" : "new" })
+							},
+						]
+					}
+				`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("jose_jws_sign.test", "serialization", "json"),
+					resource.TestCheckNoResourceAttr("jose_jws_sign.test", "alg"),
+					resource.TestCheckResourceAttr("jose_jws_sign.test", "signatures.0.alg", "ES256"),
+					resource.TestCheckResourceAttr("jose_jws_sign.test", "signatures.1.alg", "EdDSA"),
+					resource.TestMatchResourceAttr("jose_jws_sign.test", "jws", regexp.MustCompile(`^\{"payload":"[\w-]+","signatures":\[\{"protected":"[\w-]+","signature":"[\w-]+"\},\{"protected":"[\w-]+","header":\{"rotation":"new"\},"signature":"[\w-]+"\}\]\}$`)),
+				),
+			},
+			{
+				Config: `
+					resource "jose_jws_sign" "test" {
+						payload     = "this-is-a-document"
+						private_key = file("./fixtures/ed25519.pem")
+						alg         = "ES256"
+					}
+				`,
+				ExpectError: regexp.MustCompile(`algorithm "ES256" cannot be used with this private key, use "EdDSA"`),
+			},
+			// Header parameters must be a JSON object
+			{
+				Config: `
+					resource "jose_jws_sign" "test" {
+						payload       = "this-is-a-document"
+						serialization = "json"
+						signatures = [
+							{
+								private_key            = file("./fixtures/ecdsa.pem")
+								kid                    = "this-is-the-old-key-id"
+								protected_headers_json = jsonencode(null)
+							},
+						]
+					}
+				`,
+				ExpectError: regexp.MustCompile(`header parameters must be a JSON object`),
+			},
+			// Detached signature over an unencoded payload
+			{
+				Config: `
+					resource "jose_jws_sign" "test" {
+						payload           = jsonencode({ "amount" : "10.00" })
+						private_key       = file("./fixtures/ecdsa.pem")
+						detached          = true
+						unencoded_payload = true
+					}
+				`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestMatchResourceAttr("jose_jws_sign.test", "jws", regexp.MustCompile(`^[\w-]+\.\.[\w-]+$`)),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
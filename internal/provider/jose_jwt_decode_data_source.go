@@ -0,0 +1,161 @@
+// Copyright (c) HashiCorp, Inc.
+// Copyright (c) Tze Liang
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var (
+	_ datasource.DataSource = &joseJwtDecodeDataSource{}
+)
+
+func NewJoseJwtDecodeDataSource() datasource.DataSource {
+	return &joseJwtDecodeDataSource{}
+}
+
+// joseJwtDecodeDataSource defines the data source implementation.
+type joseJwtDecodeDataSource struct{}
+
+// joseJwtDecodeDataSourceModel describes the data source data model.
+type joseJwtDecodeDataSourceModel struct {
+	JWT       types.String  `tfsdk:"jwt"`
+	Header    types.Dynamic `tfsdk:"header"`
+	Claims    types.Dynamic `tfsdk:"claims"`
+	Alg       types.String  `tfsdk:"alg"`
+	KID       types.String  `tfsdk:"kid"`
+	ExpiresAt types.String  `tfsdk:"expires_at"`
+	IssuedAt  types.String  `tfsdk:"issued_at"`
+}
+
+func (d *joseJwtDecodeDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_jwt_decode"
+}
+
+func (d *joseJwtDecodeDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Decodes the header and claims of a JWT. The signature is **not** verified, use `jose_jwt_verify` for that.",
+		Attributes: map[string]schema.Attribute{
+			"jwt": schema.StringAttribute{
+				Required:            true,
+				Sensitive:           true,
+				MarkdownDescription: "The JWT to decode, in compact serialization.",
+			},
+			"header": schema.DynamicAttribute{
+				Computed:            true,
+				MarkdownDescription: "The JWT header as an object.",
+			},
+			"claims": schema.DynamicAttribute{
+				Computed:            true,
+				MarkdownDescription: "The JWT claims as an object. Numeric claims keep their full precision.",
+			},
+			"alg": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The `alg` header.",
+			},
+			"kid": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The `kid` header, if any.",
+			},
+			"expires_at": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The `exp` claim in RFC3339 format, if any.",
+			},
+			"issued_at": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The `iat` claim in RFC3339 format, if any.",
+			},
+		},
+	}
+}
+
+func (d *joseJwtDecodeDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+}
+
+func (d *joseJwtDecodeDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var (
+		data   joseJwtDecodeDataSourceModel
+		header struct {
+			Alg string `json:"alg"`
+			KID string `json:"kid"`
+		}
+		claims struct {
+			Exp json.Number `json:"exp"`
+			Iat json.Number `json:"iat"`
+		}
+	)
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	headerJSON, claimsJSON, err := decodeJWTSegments(data.JWT.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid JWT", err.Error())
+		return
+	}
+
+	headerValue, err := jsonToValue(ctx, headerJSON)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid JWT header", err.Error())
+		return
+	}
+	claimsValue, err := jsonToValue(ctx, claimsJSON)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid JWT claims", err.Error())
+		return
+	}
+
+	// Errors are ignored as unexpected types are left out of the
+	// convenience attributes, they are still available in header and claims.
+	_ = json.Unmarshal(headerJSON, &header)
+	decoder := json.NewDecoder(bytes.NewReader(claimsJSON))
+	decoder.UseNumber()
+	_ = decoder.Decode(&claims)
+
+	data.Header = types.DynamicValue(headerValue)
+	data.Claims = types.DynamicValue(claimsValue)
+	data.Alg = types.StringValue(header.Alg)
+	data.KID = types.StringNull()
+	if header.KID != "" {
+		data.KID = types.StringValue(header.KID)
+	}
+	data.ExpiresAt = numericDateToRFC3339(claims.Exp)
+	data.IssuedAt = numericDateToRFC3339(claims.Iat)
+
+	// Write logs using the tflog package
+	// Documentation: https://terraform.io/plugin/log
+	tflog.Trace(ctx, "read a data source")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// Convert a NumericDate claim into RFC3339 format.  Returns null when the
+// claim is missing or not a number.
+func numericDateToRFC3339(n json.Number) types.String {
+	seconds, err := n.Float64()
+	if err != nil {
+		return types.StringNull()
+	}
+
+	whole, frac := math.Modf(seconds)
+
+	return types.StringValue(time.Unix(int64(whole), int64(frac*float64(time.Second))).UTC().Format(time.RFC3339))
+}
@@ -0,0 +1,60 @@
+// Copyright (c) HashiCorp, Inc.
+// Copyright (c) Tze Liang
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccJoseJwtDecodeDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config: `
+					resource "jose_jwt_sign" "test" {
+						private_key = file("./fixtures/ed25519.pem")
+						kid         = "this-is-a-key-id-for-ed25519-key"
+						claims_json = jsonencode({
+							"sub" : "1234567890",
+							"iat" : 1516239022,
+							"exp" : 1516242622,
+							"custom_list" : ["item1", "item2"]
+						})
+					}
+
+					data "jose_jwt_decode" "test" {
+						jwt = jose_jwt_sign.test.jwt
+					}
+
+					output "sub" {
+						value = data.jose_jwt_decode.test.claims.sub
+					}
+
+					output "custom_item" {
+						value = data.jose_jwt_decode.test.claims.custom_list[1]
+					}
+
+					output "typ" {
+						value = data.jose_jwt_decode.test.header.typ
+					}
+				`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.jose_jwt_decode.test", "alg", "EdDSA"),
+					resource.TestCheckResourceAttr("data.jose_jwt_decode.test", "kid", "this-is-a-key-id-for-ed25519-key"),
+					resource.TestCheckResourceAttr("data.jose_jwt_decode.test", "issued_at", "2018-01-18T01:30:22Z"),
+					resource.TestCheckResourceAttr("data.jose_jwt_decode.test", "expires_at", "2018-01-18T02:30:22Z"),
+					resource.TestCheckOutput("sub", "1234567890"),
+					resource.TestCheckOutput("custom_item", "item2"),
+					resource.TestCheckOutput("typ", "JWT"),
+				),
+			},
+		},
+	})
+}
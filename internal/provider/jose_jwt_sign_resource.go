@@ -7,8 +7,12 @@ package provider
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"time"
 
+	"github.com/go-jose/go-jose/v4"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -18,8 +22,10 @@ import (
 
 // Ensure provider defined types fully satisfy framework interfaces.
 var (
-	_ resource.Resource                = &joseJwtSignResource{}
-	_ resource.ResourceWithImportState = &joseJwtSignResource{}
+	_ resource.Resource                     = &joseJwtSignResource{}
+	_ resource.ResourceWithImportState      = &joseJwtSignResource{}
+	_ resource.ResourceWithConfigValidators = &joseJwtSignResource{}
+	_ resource.ResourceWithModifyPlan       = &joseJwtSignResource{}
 )
 
 func NewJoseJwtSignResource() resource.Resource {
@@ -31,11 +37,27 @@ type joseJwtSignResource struct{}
 
 // jwtResourceModel describes the resource data model.
 type joseJwtSignResourceModel struct {
-	PrivateKey types.String `tfsdk:"private_key"`
-	Alg        types.String `tfsdk:"alg"`
-	KID        types.String `tfsdk:"kid"`
-	ClaimsJSON types.String `tfsdk:"claims_json"`
-	JWT        types.String `tfsdk:"jwt"`
+	PrivateKey           types.String `tfsdk:"private_key"`
+	PrivateJWK           types.String `tfsdk:"private_jwk"`
+	PrivateJWKS          types.String `tfsdk:"private_jwks"`
+	PrivateKeyPassphrase types.String `tfsdk:"private_key_passphrase"`
+	Secret               types.String `tfsdk:"secret"`
+	SecretEncoding       types.String `tfsdk:"secret_encoding"`
+	Alg                  types.String `tfsdk:"alg"`
+	KID                  types.String `tfsdk:"kid"`
+	ClaimsJSON           types.String `tfsdk:"claims_json"`
+	ExpiresIn            types.String `tfsdk:"expires_in"`
+	NotBeforeOffset      types.String `tfsdk:"not_before_offset"`
+	SetIssuedAt          types.Bool   `tfsdk:"set_issued_at"`
+	GenerateJTI          types.Bool   `tfsdk:"generate_jti"`
+	EarlyRenewal         types.String `tfsdk:"early_renewal"`
+	Keepers              types.Map    `tfsdk:"keepers"`
+	ReadyForRenewal      types.Bool   `tfsdk:"ready_for_renewal"`
+	IssuedAt             types.String `tfsdk:"issued_at"`
+	NotBefore            types.String `tfsdk:"not_before"`
+	ExpiresAt            types.String `tfsdk:"expires_at"`
+	JTI                  types.String `tfsdk:"jti"`
+	JWT                  types.String `tfsdk:"jwt"`
 }
 
 func (r *joseJwtSignResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -45,11 +67,22 @@ func (r *joseJwtSignResource) Metadata(ctx context.Context, req resource.Metadat
 func (r *joseJwtSignResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		// This description is used by the documentation generator and the language server.
-		MarkdownDescription: "Creates a JWT. Supports RSA, ECDSA and EdDSA keys.",
+		MarkdownDescription: "Creates a JWT. Supports RSA, ECDSA and EdDSA keys, and HMAC with a symmetric secret.",
 		Attributes:          jwtSchema,
 	}
 }
 
+func (r *joseJwtSignResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		resourcevalidator.ExactlyOneOf(
+			path.MatchRoot("private_key"),
+			path.MatchRoot("private_jwk"),
+			path.MatchRoot("private_jwks"),
+			path.MatchRoot("secret"),
+		),
+	}
+}
+
 func (r *joseJwtSignResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 }
 
@@ -71,16 +104,102 @@ func (r *joseJwtSignResource) Create(ctx context.Context, req resource.CreateReq
 		resp.Diagnostics.AddError("Invalid claims JSON", err.Error())
 		return
 	}
+	// A JSON null resets the claims, which must be an object (RFC 7519).
+	if claims == nil {
+		resp.Diagnostics.AddAttributeError(path.Root("claims_json"), "Invalid claims JSON", "claims_json must be a JSON object")
+		return
+	}
 
-	// Parse PEM into correct private key type
-	privateKey, err := parsePrivateKey([]byte(data.PrivateKey.ValueString()), data.Alg)
-	if err != nil {
-		resp.Diagnostics.AddError("Invalid private key", err.Error())
+	// Add the registered claims relative to the signing time, which is
+	// truncated to the precision of NumericDate values.
+	var generated jwtGeneratedClaims
+	now := time.Now().UTC().Truncate(time.Second)
+	if data.SetIssuedAt.ValueBool() {
+		generated.IssuedAt = &now
+	}
+	if !data.NotBeforeOffset.IsNull() {
+		offset, _ := time.ParseDuration(data.NotBeforeOffset.ValueString())
+		notBefore := now.Add(offset)
+		generated.NotBefore = &notBefore
+	}
+	if !data.ExpiresIn.IsNull() {
+		expiresIn, _ := time.ParseDuration(data.ExpiresIn.ValueString())
+		expiresAt := now.Add(expiresIn)
+		generated.ExpiresAt = &expiresAt
+	}
+	if data.GenerateJTI.ValueBool() {
+		jti, err := generateUUID()
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to generate JWT ID", err.Error())
+			return
+		}
+		generated.ID = jti
+	}
+	if err := generated.merge(claims); err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("claims_json"), "Conflicting claims", err.Error())
 		return
 	}
 
+	var (
+		privateKey PrivateKey
+		err        error
+	)
+	kid := data.KID.ValueString()
+	if !data.Secret.IsNull() {
+		privateKey, err = parseSecret(data.Secret.ValueString(), data.SecretEncoding.ValueString(), data.Alg)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("secret"), "Invalid secret", err.Error())
+			return
+		}
+	} else if !data.PrivateJWK.IsNull() || !data.PrivateJWKS.IsNull() {
+		jwkJSON := []byte(data.PrivateJWK.ValueString())
+		attribute := path.Root("private_jwk")
+		if !data.PrivateJWKS.IsNull() {
+			attribute = path.Root("private_jwks")
+			jwkJSON, err = selectSigningJWK([]byte(data.PrivateJWKS.ValueString()), kid, "sign")
+			if err != nil {
+				resp.Diagnostics.AddAttributeError(attribute, "Invalid private JWK Set", err.Error())
+				return
+			}
+		}
+
+		var jwk *jose.JSONWebKey
+		privateKey, jwk, err = parseSigningJWK(jwkJSON, data.Alg)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(attribute, "Invalid private JWK", err.Error())
+			return
+		}
+		if kid == "" {
+			kid = jwk.KeyID
+		}
+	} else {
+		keyPEM := []byte(data.PrivateKey.ValueString())
+		if !data.PrivateKeyPassphrase.IsNull() {
+			keyPEM, err = decryptPrivateKey(keyPEM, data.PrivateKeyPassphrase.ValueString())
+			if err != nil {
+				resp.Diagnostics.AddAttributeError(path.Root("private_key_passphrase"), "Failed to decrypt private key", err.Error())
+				return
+			}
+		}
+
+		// Parse PEM into correct private key type
+		privateKey, err = parsePrivateKey(keyPEM, data.Alg)
+		if err != nil {
+			resp.Diagnostics.AddError("Invalid private key", err.Error())
+			return
+		}
+		ignored, err := checkPrivateKeyAlgorithm(privateKey, data.Alg)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("alg"), "Invalid algorithm", err.Error())
+			return
+		}
+		if ignored {
+			resp.Diagnostics.AddAttributeWarning(path.Root("alg"), "Ignored algorithm", fmt.Sprintf("algorithm %q only applies to RSA keys, the JWT is signed with %q", data.Alg.ValueString(), privateKey.algorithm()))
+		}
+	}
+
 	// Create the JWT token
-	token, err := privateKey.sign(claims, data.KID.ValueString())
+	token, err := privateKey.sign(claims, kid)
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to sign JWT", err.Error())
 		return
@@ -89,6 +208,14 @@ func (r *joseJwtSignResource) Create(ctx context.Context, req resource.CreateReq
 	// For the purposes of this example code, hardcoding a response value to
 	// save into the Terraform state.
 	data.JWT = types.StringValue(token)
+	if data.Alg.IsUnknown() || data.Alg.IsNull() {
+		data.Alg = types.StringValue(privateKey.algorithm())
+	}
+	data.IssuedAt = timeStringOrNull(generated.IssuedAt)
+	data.NotBefore = timeStringOrNull(generated.NotBefore)
+	data.ExpiresAt = timeStringOrNull(generated.ExpiresAt)
+	data.JTI = stringOrNull(generated.ID)
+	data.ReadyForRenewal = types.BoolValue(false)
 
 	// Write logs using the tflog package
 	// Documentation: https://terraform.io/plugin/log
@@ -108,6 +235,14 @@ func (r *joseJwtSignResource) Read(ctx context.Context, req resource.ReadRequest
 		return
 	}
 
+	// Report a JWT that is due for renewal, which is then replaced by ModifyPlan.
+	ready, err := jwtReadyForRenewal(data.JWT.ValueString(), data.EarlyRenewal.ValueString(), time.Now())
+	if err != nil {
+		resp.Diagnostics.AddWarning("Failed to check the JWT expiration time", err.Error())
+	} else {
+		data.ReadyForRenewal = types.BoolValue(ready)
+	}
+
 	// If applicable, this is a great opportunity to initialize any necessary
 	// provider client data and make a call using it.
 	// httpResp, err := r.client.Do(httpReq)
@@ -121,19 +256,71 @@ func (r *joseJwtSignResource) Read(ctx context.Context, req resource.ReadRequest
 }
 
 func (r *joseJwtSignResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	var data joseJwtSignResourceModel
+	var data, state joseJwtSignResourceModel
 
 	// Read Terraform plan data into the model
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
 
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	// Only early_renewal can be updated in place, the JWT is unchanged.
+	data.Alg = state.Alg
+	data.IssuedAt = state.IssuedAt
+	data.NotBefore = state.NotBefore
+	data.ExpiresAt = state.ExpiresAt
+	data.JTI = state.JTI
+	data.JWT = state.JWT
+	// The readiness is planned by ModifyPlan, and refreshed by Read.
+	if data.ReadyForRenewal.IsUnknown() {
+		ready, err := jwtReadyForRenewal(data.JWT.ValueString(), data.EarlyRenewal.ValueString(), time.Now())
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to check the JWT expiration time", err.Error())
+			return
+		}
+		data.ReadyForRenewal = types.BoolValue(ready)
+	}
+
 	// Save updated data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
+// Plan the replacement of a JWT that has expired or expires within the early
+// renewal window, so that it is signed again.
+func (r *joseJwtSignResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to renew on creation or destruction.
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var (
+		state        joseJwtSignResourceModel
+		earlyRenewal types.String
+	)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("early_renewal"), &earlyRenewal)...)
+
+	if resp.Diagnostics.HasError() || earlyRenewal.IsUnknown() {
+		return
+	}
+
+	ready, err := jwtReadyForRenewal(state.JWT.ValueString(), earlyRenewal.ValueString(), time.Now())
+	if err != nil {
+		resp.Diagnostics.AddWarning("Failed to check the JWT expiration time", err.Error())
+		return
+	}
+	if !ready {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("ready_for_renewal"), types.BoolValue(false))...)
+		return
+	}
+
+	tflog.Debug(ctx, "JWT is ready for renewal, planning its replacement")
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("ready_for_renewal"), types.BoolUnknown())...)
+	resp.RequiresReplace = append(resp.RequiresReplace, path.Root("ready_for_renewal"))
+}
+
 func (r *joseJwtSignResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
 	var data joseJwtSignResourceModel
 
@@ -148,3 +335,12 @@ func (r *joseJwtSignResource) Delete(ctx context.Context, req resource.DeleteReq
 func (r *joseJwtSignResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
 	resource.ImportStatePassthroughID(ctx, path.Root("jwt"), req, resp)
 }
+
+// Convert an optional time into an RFC 3339 string, or a null value.
+func timeStringOrNull(t *time.Time) types.String {
+	if t == nil {
+		return types.StringNull()
+	}
+
+	return types.StringValue(t.Format(time.RFC3339))
+}
@@ -0,0 +1,413 @@
+// Copyright (c) HashiCorp, Inc.
+// Copyright (c) Tze Liang
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+)
+
+func TestAccJoseJwtSignResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: `
+					resource "jose_jwt_sign" "ecdsa" {
+						private_key = file("./fixtures/ecdsa.pem")
+						claims_json = jsonencode({ "sub" : "1234567890" })
+					}
+
+					resource "jose_jwt_sign" "hmac" {
+						secret          = base64encode("this-is-a-shared-secret-of-at-least-48-bytes-long")
+						secret_encoding = "base64"
+						alg             = "HS384"
+						kid             = "this-is-a-key-id-for-hmac-secret"
+						claims_json     = jsonencode({ "sub" : "1234567890" })
+					}
+
+					data "jose_jwt_decode" "hmac" {
+						jwt = jose_jwt_sign.hmac.jwt
+					}
+
+					resource "jose_jwt_sign" "ed25519" {
+						private_key = file("./fixtures/ed25519.pem")
+						claims_json = jsonencode({ "sub" : "1234567890" })
+					}
+
+					data "jose_jwt_decode" "ed25519" {
+						jwt = jose_jwt_sign.ed25519.jwt
+					}
+
+					resource "jose_jwt_sign" "ecdsa_rs256" {
+						private_key = file("./fixtures/ecdsa.pem")
+						alg         = "RS256"
+						claims_json = jsonencode({ "sub" : "1234567890" })
+					}
+
+					data "jose_jwt_decode" "ecdsa_rs256" {
+						jwt = jose_jwt_sign.ecdsa_rs256.jwt
+					}
+				`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("jose_jwt_sign.ecdsa", "alg", "ES256"),
+					resource.TestCheckResourceAttr("jose_jwt_sign.hmac", "alg", "HS384"),
+					resource.TestCheckResourceAttr("data.jose_jwt_decode.hmac", "alg", "HS384"),
+					resource.TestCheckResourceAttr("data.jose_jwt_decode.hmac", "kid", "this-is-a-key-id-for-hmac-secret"),
+					resource.TestCheckResourceAttr("data.jose_jwt_decode.ed25519", "alg", "EdDSA"),
+					resource.TestCheckResourceAttr("data.jose_jwt_decode.ed25519", "header.kid", ""),
+					resource.TestCheckResourceAttr("jose_jwt_sign.ecdsa_rs256", "alg", "RS256"),
+					resource.TestCheckResourceAttr("data.jose_jwt_decode.ecdsa_rs256", "alg", "ES256"),
+				),
+			},
+			{
+				Config: `
+					resource "jose_jwt_sign" "ecdsa" {
+						private_key = file("./fixtures/ecdsa.pem")
+						alg         = "ES384"
+						claims_json = jsonencode({ "sub" : "1234567890" })
+					}
+				`,
+				ExpectError: regexp.MustCompile(`algorithm "ES384" cannot be used with this private key, use "ES256"`),
+			},
+			// RSASSA-PSS, verified against the JWK of the same key
+			{
+				Config: `
+					resource "jose_key_pair" "rsa" {
+						algorithm = "RSA"
+						alg       = "PS256"
+					}
+
+					resource "jose_jwt_sign" "rsa" {
+						private_key = jose_key_pair.rsa.private_key_pem
+						alg         = "PS256"
+						kid         = jose_key_pair.rsa.kid
+						claims_json = jsonencode({ "sub" : "1234567890" })
+					}
+
+					data "jose_jwt_verify" "rsa" {
+						jwt  = jose_jwt_sign.rsa.jwt
+						jwks = jsonencode({ "keys" : [jsondecode(jose_key_pair.rsa.public_jwk)] })
+					}
+				`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("jose_jwt_sign.rsa", "alg", "PS256"),
+					resource.TestCheckResourceAttr("data.jose_jwt_verify.rsa", "valid", "true"),
+					resource.TestCheckResourceAttr("data.jose_jwt_verify.rsa", "alg", "PS256"),
+				),
+			},
+			// ES256K, verified against the JWK of the same key
+			{
+				Config: `
+					resource "jose_jwk" "secp256k1" {
+						public_key = file("./fixtures/secp256k1-pub.pem")
+					}
+
+					resource "jose_jwt_sign" "secp256k1" {
+						private_key = file("./fixtures/secp256k1.pem")
+						kid         = jose_jwk.secp256k1.kid
+						claims_json = jsonencode({ "sub" : "1234567890" })
+					}
+
+					data "jose_jwt_verify" "secp256k1" {
+						jwt  = jose_jwt_sign.secp256k1.jwt
+						jwks = jsonencode({ "keys" : [jsondecode(jose_jwk.secp256k1.jwk)] })
+					}
+
+					data "jose_jwt_verify" "p256" {
+						jwt             = jose_jwt_sign.secp256k1.jwt
+						public_key      = file("./fixtures/ecdsa-pub.pem")
+						fail_on_invalid = false
+					}
+				`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("jose_jwt_sign.secp256k1", "alg", "ES256K"),
+					resource.TestCheckResourceAttr("data.jose_jwt_verify.secp256k1", "valid", "true"),
+					resource.TestCheckResourceAttr("data.jose_jwt_verify.secp256k1", "alg", "ES256K"),
+					resource.TestCheckResourceAttr("data.jose_jwt_verify.p256", "valid", "false"),
+				),
+			},
+			// PKCS #8 keys are loaded by their actual key type
+			{
+				Config: `
+					resource "jose_jwt_sign" "pkcs8" {
+						private_key = file("./fixtures/rsa.pem")
+						alg         = "RS384"
+						claims_json = jsonencode({ "sub" : "1234567890" })
+					}
+				`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("jose_jwt_sign.pkcs8", "alg", "RS384"),
+				),
+			},
+			{
+				Config: `
+					resource "jose_jwt_sign" "public" {
+						private_key = file("./fixtures/rsa-pub.pem")
+						claims_json = jsonencode({ "sub" : "1234567890" })
+					}
+				`,
+				ExpectError: regexp.MustCompile(`tried PKCS #1 \(RSA\), SEC 1 \(ECDSA\) and PKCS #8`),
+			},
+			// Passphrase protected private keys
+			{
+				Config: `
+					resource "jose_jwt_sign" "pkcs8" {
+						private_key            = file("./fixtures/ecdsa-encrypted.pem")
+						private_key_passphrase = "correct-horse"
+						claims_json            = jsonencode({ "sub" : "1234567890" })
+					}
+
+					resource "jose_jwt_sign" "legacy" {
+						private_key            = file("./fixtures/rsa-legacy-encrypted.pem")
+						private_key_passphrase = "pw"
+						claims_json            = jsonencode({ "sub" : "1234567890" })
+					}
+
+					data "jose_jwt_verify" "pkcs8" {
+						jwt        = jose_jwt_sign.pkcs8.jwt
+						public_key = file("./fixtures/ecdsa-pub.pem")
+					}
+				`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("jose_jwt_sign.pkcs8", "alg", "ES256"),
+					resource.TestCheckResourceAttr("data.jose_jwt_verify.pkcs8", "valid", "true"),
+					resource.TestCheckResourceAttr("jose_jwt_sign.legacy", "alg", "RS256"),
+				),
+			},
+			{
+				Config: `
+					resource "jose_jwt_sign" "pkcs8" {
+						private_key = file("./fixtures/ecdsa-encrypted.pem")
+						claims_json = jsonencode({ "sub" : "1234567890" })
+					}
+				`,
+				ExpectError: regexp.MustCompile(`the private key is encrypted and requires a passphrase`),
+			},
+			{
+				Config: `
+					resource "jose_jwt_sign" "pkcs8" {
+						private_key            = file("./fixtures/ecdsa-encrypted.pem")
+						private_key_passphrase = "wrong"
+						claims_json            = jsonencode({ "sub" : "1234567890" })
+					}
+				`,
+				ExpectError: regexp.MustCompile(`the passphrase may be incorrect`),
+			},
+			// Private JWK and private JWK Set, with the alg and kid of the JWK
+			{
+				Config: `
+					resource "jose_jwk" "rsa" {
+						private_key = file("./fixtures/rsa.pem")
+						alg         = "PS384"
+					}
+
+					resource "jose_jwk" "ecdsa" {
+						private_key = file("./fixtures/ecdsa.pem")
+						kid         = "this-is-a-key-id-for-ecdsa-key"
+					}
+
+					resource "jose_jwk" "encryption" {
+						private_key = file("./fixtures/ecdsa.pem")
+						kid         = "this-is-a-key-id-for-ecdsa-encryption-key"
+						use         = "enc"
+					}
+
+					resource "jose_jwt_sign" "jwk" {
+						private_jwk = jose_jwk.rsa.private_jwk
+						claims_json = jsonencode({ "sub" : "1234567890" })
+					}
+
+					resource "jose_jwk" "verification" {
+						private_key = file("./fixtures/rsa.pem")
+						key_ops     = ["verify"]
+					}
+
+					resource "jose_jwt_sign" "jwks" {
+						private_jwks = jsonencode({ "keys" : [jsondecode(jose_jwk.encryption.private_jwk), jsondecode(jose_jwk.verification.private_jwk), jsondecode(jose_jwk.ecdsa.private_jwk)] })
+						claims_json  = jsonencode({ "sub" : "1234567890" })
+					}
+
+					data "jose_jwt_decode" "jwk" {
+						jwt = jose_jwt_sign.jwk.jwt
+					}
+
+					data "jose_jwt_decode" "jwks" {
+						jwt = jose_jwt_sign.jwks.jwt
+					}
+				`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("jose_jwt_sign.jwk", "alg", "PS384"),
+					resource.TestCheckResourceAttrPair("data.jose_jwt_decode.jwk", "kid", "jose_jwk.rsa", "kid"),
+					resource.TestCheckResourceAttr("jose_jwt_sign.jwks", "alg", "ES256"),
+					resource.TestCheckResourceAttr("data.jose_jwt_decode.jwks", "kid", "this-is-a-key-id-for-ecdsa-key"),
+				),
+			},
+			{
+				Config: `
+					resource "jose_jwk" "encryption" {
+						private_key = file("./fixtures/ecdsa.pem")
+						use         = "enc"
+					}
+
+					resource "jose_jwt_sign" "jwk" {
+						private_jwk = jose_jwk.encryption.private_jwk
+						claims_json = jsonencode({ "sub" : "1234567890" })
+					}
+				`,
+				ExpectError: regexp.MustCompile(`cannot be used for signing`),
+			},
+			// Registered claims relative to the signing time
+			{
+				Config: `
+					resource "jose_jwt_sign" "relative" {
+						private_key       = file("./fixtures/ecdsa.pem")
+						claims_json       = jsonencode({ "sub" : "1234567890" })
+						set_issued_at     = true
+						not_before_offset = "-30s"
+						expires_in        = "1h"
+						generate_jti      = true
+					}
+
+					data "jose_jwt_verify" "relative" {
+						jwt        = jose_jwt_sign.relative.jwt
+						public_key = file("./fixtures/ecdsa-pub.pem")
+					}
+				`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestMatchResourceAttr("jose_jwt_sign.relative", "issued_at", regexp.MustCompile(`^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}Z$`)),
+					resource.TestCheckResourceAttrSet("jose_jwt_sign.relative", "not_before"),
+					resource.TestCheckResourceAttrSet("jose_jwt_sign.relative", "expires_at"),
+					resource.TestMatchResourceAttr("jose_jwt_sign.relative", "jti", regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)),
+					resource.TestCheckResourceAttr("data.jose_jwt_verify.relative", "valid", "true"),
+					resource.TestMatchResourceAttr("data.jose_jwt_verify.relative", "claims_json", regexp.MustCompile(`"exp":\d+,"iat":\d+,"jti":"[0-9a-f-]{36}","nbf":\d+`)),
+				),
+			},
+			{
+				Config: `
+					resource "jose_jwt_sign" "relative" {
+						private_key = file("./fixtures/ecdsa.pem")
+						claims_json = jsonencode({ "sub" : "1234567890", "exp" : 1516239022 })
+						expires_in  = "1h"
+					}
+				`,
+				ExpectError: regexp.MustCompile(`claim "exp" is set both in claims_json and by the provider`),
+			},
+			{
+				Config: `
+					resource "jose_jwt_sign" "relative" {
+						private_key = file("./fixtures/ecdsa.pem")
+						claims_json = jsonencode({ "sub" : "1234567890" })
+						expires_in  = "-1h"
+					}
+				`,
+				ExpectError: regexp.MustCompile(`value must be a positive duration`),
+			},
+			{
+				Config: `
+					resource "jose_jwt_sign" "relative" {
+						private_key   = file("./fixtures/ecdsa.pem")
+						claims_json   = jsonencode(null)
+						set_issued_at = true
+					}
+				`,
+				ExpectError: regexp.MustCompile(`claims_json must be a JSON object`),
+			},
+			// Early renewal plans the replacement of a JWT expiring within the window
+			{
+				Config: `
+					resource "jose_jwt_sign" "renewal" {
+						private_key   = file("./fixtures/ecdsa.pem")
+						claims_json   = jsonencode({ "sub" : "1234567890" })
+						expires_in    = "1h"
+						early_renewal = "2h"
+					}
+				`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("jose_jwt_sign.renewal", "ready_for_renewal", "false"),
+				),
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: `
+					resource "jose_jwt_sign" "renewal" {
+						private_key   = file("./fixtures/ecdsa.pem")
+						claims_json   = jsonencode({ "sub" : "1234567890" })
+						expires_in    = "1h"
+						early_renewal = "30m"
+					}
+				`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("jose_jwt_sign.renewal", "ready_for_renewal", "false"),
+					resource.TestCheckResourceAttrSet("jose_jwt_sign.renewal", "expires_at"),
+				),
+			},
+			// Changing only early_renewal updates the resource in place
+			{
+				Config: `
+					resource "jose_jwt_sign" "renewal" {
+						private_key   = file("./fixtures/ecdsa.pem")
+						claims_json   = jsonencode({ "sub" : "1234567890" })
+						expires_in    = "1h"
+						early_renewal = "20m"
+					}
+				`,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("jose_jwt_sign.renewal", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("jose_jwt_sign.renewal", "early_renewal", "20m"),
+					resource.TestCheckResourceAttr("jose_jwt_sign.renewal", "ready_for_renewal", "false"),
+				),
+			},
+			// Keepers force signing a new JWT
+			{
+				Config: `
+					resource "jose_jwt_sign" "keepers" {
+						private_key  = file("./fixtures/ecdsa.pem")
+						claims_json  = jsonencode({ "sub" : "1234567890" })
+						generate_jti = true
+						keepers      = { deploy_id = "1" }
+					}
+				`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("jose_jwt_sign.keepers", "keepers.deploy_id", "1"),
+				),
+			},
+			{
+				Config: `
+					resource "jose_jwt_sign" "keepers" {
+						private_key  = file("./fixtures/ecdsa.pem")
+						claims_json  = jsonencode({ "sub" : "1234567890" })
+						generate_jti = true
+						keepers      = { deploy_id = "2" }
+					}
+				`,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("jose_jwt_sign.keepers", plancheck.ResourceActionReplace),
+					},
+				},
+			},
+			// Secrets shorter than the hash output are rejected
+			{
+				Config: `
+					resource "jose_jwt_sign" "short" {
+						secret      = "too-short"
+						claims_json = jsonencode({ "sub" : "1234567890" })
+					}
+				`,
+				ExpectError: regexp.MustCompile(`secret must be at least 32 bytes long for HS256`),
+			},
+		},
+	})
+}
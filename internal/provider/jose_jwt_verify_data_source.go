@@ -0,0 +1,249 @@
+// Copyright (c) HashiCorp, Inc.
+// Copyright (c) Tze Liang
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"crypto"
+	"encoding/json"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/datasourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var (
+	_ datasource.DataSource                     = &joseJwtVerifyDataSource{}
+	_ datasource.DataSourceWithConfigValidators = &joseJwtVerifyDataSource{}
+)
+
+func NewJoseJwtVerifyDataSource() datasource.DataSource {
+	return &joseJwtVerifyDataSource{}
+}
+
+// joseJwtVerifyDataSource defines the data source implementation.
+type joseJwtVerifyDataSource struct{}
+
+// joseJwtVerifyDataSourceModel describes the data source data model.
+type joseJwtVerifyDataSourceModel struct {
+	JWT           types.String `tfsdk:"jwt"`
+	PublicKey     types.String `tfsdk:"public_key"`
+	PublicJWK     types.String `tfsdk:"public_jwk"`
+	JWKS          types.String `tfsdk:"jwks"`
+	Issuer        types.String `tfsdk:"issuer"`
+	Audience      types.String `tfsdk:"audience"`
+	Leeway        types.String `tfsdk:"leeway"`
+	ValidateTime  types.Bool   `tfsdk:"validate_time"`
+	FailOnInvalid types.Bool   `tfsdk:"fail_on_invalid"`
+	Alg           types.String `tfsdk:"alg"`
+	KID           types.String `tfsdk:"kid"`
+	Valid         types.Bool   `tfsdk:"valid"`
+	Reason        types.String `tfsdk:"reason"`
+	HeaderJSON    types.String `tfsdk:"header_json"`
+	ClaimsJSON    types.String `tfsdk:"claims_json"`
+}
+
+func (d *joseJwtVerifyDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_jwt_verify"
+}
+
+func (d *joseJwtVerifyDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Verifies the signature and claims of a JWT against a public key, a JWK or a JWK Set. Supports RSA, ECDSA and EdDSA keys.",
+		Attributes: map[string]schema.Attribute{
+			"jwt": schema.StringAttribute{
+				Required:            true,
+				Sensitive:           true,
+				MarkdownDescription: "The JWT to verify, in compact serialization.",
+			},
+			"public_key": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Public key in PEM format. Exactly one of `public_key`, `public_jwk` or `jwks` must be set.",
+			},
+			"public_jwk": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Public key as a JWK in JSON format. When the JWK has an `alg`, only that algorithm is accepted. Exactly one of `public_key`, `public_jwk` or `jwks` must be set.",
+			},
+			"jwks": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "JWK Set in JSON format. The key is selected by the `kid` header of the JWT. Keys intended for encryption by their `use`, or whose `key_ops` do not include `verify`, are ignored. Exactly one of `public_key`, `public_jwk` or `jwks` must be set.",
+			},
+			"issuer": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Expected `iss` claim. Not checked when unset.",
+			},
+			"audience": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Expected `aud` claim. Not checked when unset.",
+			},
+			"leeway": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Leeway allowed when validating time based claims, as a duration such as \"30s\". Defaults to \"0s\".",
+				Validators: []validator.String{
+					isDuration(),
+				},
+			},
+			"validate_time": schema.BoolAttribute{
+				Optional:            true,
+				MarkdownDescription: "Whether to validate the `exp`, `nbf` and `iat` claims when present. Defaults to `true`.",
+			},
+			"fail_on_invalid": schema.BoolAttribute{
+				Optional:            true,
+				MarkdownDescription: "Whether an invalid JWT fails the plan. When `false`, `valid` and `reason` report the result instead, for use in `check` blocks. Defaults to `true`.",
+			},
+			"alg": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Algorithm from the JWT header.",
+			},
+			"kid": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Key ID from the JWT header, if any.",
+			},
+			"valid": schema.BoolAttribute{
+				Computed:            true,
+				MarkdownDescription: "Whether the signature and claims of the JWT are valid.",
+			},
+			"reason": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The reason the JWT is not valid. Empty when `valid` is `true`.",
+			},
+			"header_json": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The verified header in JSON format. Null when the JWT is not valid.",
+			},
+			"claims_json": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The verified claims in JSON format. Null when the JWT is not valid.",
+			},
+		},
+	}
+}
+
+func (d *joseJwtVerifyDataSource) ConfigValidators(ctx context.Context) []datasource.ConfigValidator {
+	return []datasource.ConfigValidator{
+		datasourcevalidator.ExactlyOneOf(
+			path.MatchRoot("public_key"),
+			path.MatchRoot("public_jwk"),
+			path.MatchRoot("jwks"),
+		),
+	}
+}
+
+func (d *joseJwtVerifyDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+}
+
+func (d *joseJwtVerifyDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var (
+		data   joseJwtVerifyDataSourceModel
+		header struct {
+			Alg string `json:"alg"`
+			KID string `json:"kid"`
+		}
+		pubKey crypto.PublicKey
+		alg    string
+		leeway time.Duration
+	)
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	failOnInvalid := data.FailOnInvalid.IsNull() || data.FailOnInvalid.ValueBool()
+
+	// Report an invalid JWT either as an error or through `valid` and `reason`
+	invalid := func(reason string) {
+		if failOnInvalid {
+			resp.Diagnostics.AddError("Invalid JWT", reason)
+			return
+		}
+		data.Valid = types.BoolValue(false)
+		data.Reason = types.StringValue(reason)
+		data.HeaderJSON = types.StringNull()
+		data.ClaimsJSON = types.StringNull()
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	}
+
+	data.Alg = types.StringNull()
+	data.KID = types.StringNull()
+
+	headerJSON, claimsJSON, err := decodeJWTSegments(data.JWT.ValueString())
+	if err == nil {
+		err = json.Unmarshal(headerJSON, &header)
+	}
+	if err != nil {
+		invalid(err.Error())
+		return
+	}
+	data.Alg = types.StringValue(header.Alg)
+	if header.KID != "" {
+		data.KID = types.StringValue(header.KID)
+	}
+
+	if !data.Leeway.IsNull() {
+		leeway, _ = time.ParseDuration(data.Leeway.ValueString())
+	}
+
+	// Select the verification key
+	switch {
+	case !data.PublicKey.IsNull():
+		pubKey, err = parsePublicKey([]byte(data.PublicKey.ValueString()))
+		if err != nil {
+			resp.Diagnostics.AddError("Invalid public key", err.Error())
+			return
+		}
+	case !data.PublicJWK.IsNull():
+		jwk, err := parsePublicJWK([]byte(data.PublicJWK.ValueString()))
+		if err != nil {
+			resp.Diagnostics.AddError("Invalid public JWK", err.Error())
+			return
+		}
+		pubKey, alg = jwk.Key, jwk.Algorithm
+	default:
+		jwkJSON, err := selectSigningJWK([]byte(data.JWKS.ValueString()), header.KID, "verify")
+		if err != nil {
+			invalid(err.Error())
+			return
+		}
+		jwk, err := parsePublicJWK(jwkJSON)
+		if err != nil {
+			resp.Diagnostics.AddError("Invalid JWK in JWK Set", err.Error())
+			return
+		}
+		pubKey, alg = jwk.Key, jwk.Algorithm
+	}
+
+	err = verifyJWT(data.JWT.ValueString(), pubKey, alg, jwtValidationOptions{
+		Issuer:       data.Issuer.ValueString(),
+		Audience:     data.Audience.ValueString(),
+		Leeway:       leeway,
+		ValidateTime: data.ValidateTime.IsNull() || data.ValidateTime.ValueBool(),
+	})
+	if err != nil {
+		invalid(err.Error())
+		return
+	}
+
+	data.Valid = types.BoolValue(true)
+	data.Reason = types.StringValue("")
+	data.HeaderJSON = types.StringValue(string(headerJSON))
+	data.ClaimsJSON = types.StringValue(string(claimsJSON))
+
+	// Write logs using the tflog package
+	// Documentation: https://terraform.io/plugin/log
+	tflog.Trace(ctx, "read a data source")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,76 @@
+// Copyright (c) HashiCorp, Inc.
+// Copyright (c) Tze Liang
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccJoseJwtVerifyDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config: `
+					resource "jose_jwt_sign" "test" {
+						private_key = file("./fixtures/ecdsa.pem")
+						kid         = "this-is-a-key-id-for-ecdsa-key"
+						claims_json = jsonencode({ "iss" : "https://example.com", "sub" : "1234567890" })
+					}
+
+					resource "jose_jwk" "test" {
+						kid        = "this-is-a-key-id-for-ecdsa-key"
+						public_key = file("./fixtures/ecdsa-pub.pem")
+					}
+
+					data "jose_jwt_verify" "test" {
+						jwt    = jose_jwt_sign.test.jwt
+						jwks   = jsonencode({ "keys" : [jsondecode(jose_jwk.test.jwk)] })
+						issuer = "https://example.com"
+					}
+
+					data "jose_jwt_verify" "wrong_issuer" {
+						jwt             = jose_jwt_sign.test.jwt
+						public_key      = file("./fixtures/ecdsa-pub.pem")
+						issuer          = "https://some-other-issuer.com"
+						fail_on_invalid = false
+					}
+
+					resource "jose_jwt_sign" "future" {
+						private_key = file("./fixtures/ecdsa.pem")
+						claims_json = jsonencode({ "sub" : "1234567890", "iat" : 4102444800 })
+					}
+
+					data "jose_jwt_verify" "future" {
+						jwt             = jose_jwt_sign.future.jwt
+						public_key      = file("./fixtures/ecdsa-pub.pem")
+						fail_on_invalid = false
+					}
+
+					data "jose_jwt_verify" "future_without_time" {
+						jwt           = jose_jwt_sign.future.jwt
+						public_key    = file("./fixtures/ecdsa-pub.pem")
+						validate_time = false
+					}
+				`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.jose_jwt_verify.test", "valid", "true"),
+					resource.TestCheckResourceAttr("data.jose_jwt_verify.test", "alg", "ES256"),
+					resource.TestCheckResourceAttr("data.jose_jwt_verify.test", "kid", "this-is-a-key-id-for-ecdsa-key"),
+					resource.TestCheckResourceAttr("data.jose_jwt_verify.test", "claims_json", `{"iss":"https://example.com","sub":"1234567890"}`),
+					resource.TestCheckResourceAttr("data.jose_jwt_verify.wrong_issuer", "valid", "false"),
+					resource.TestCheckResourceAttr("data.jose_jwt_verify.wrong_issuer", "reason", "token has invalid claims: token has invalid issuer"),
+					resource.TestCheckResourceAttr("data.jose_jwt_verify.future", "valid", "false"),
+					resource.TestCheckResourceAttr("data.jose_jwt_verify.future", "reason", "token has invalid claims: token used before issued"),
+					resource.TestCheckResourceAttr("data.jose_jwt_verify.future_without_time", "valid", "true"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,138 @@
+// Copyright (c) HashiCorp, Inc.
+// Copyright (c) Tze Liang
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var (
+	_ resource.Resource = &joseKeyPairResource{}
+)
+
+func NewJoseKeyPairResource() resource.Resource {
+	return &joseKeyPairResource{}
+}
+
+// joseKeyPairResource defines the resource implementation.
+type joseKeyPairResource struct{}
+
+// joseKeyPairResourceModel describes the resource data model.
+type joseKeyPairResourceModel struct {
+	Algorithm     types.String `tfsdk:"algorithm"`
+	RSABits       types.Int64  `tfsdk:"rsa_bits"`
+	ECDSACurve    types.String `tfsdk:"ecdsa_curve"`
+	Alg           types.String `tfsdk:"alg"`
+	KID           types.String `tfsdk:"kid"`
+	Use           types.String `tfsdk:"use"`
+	PrivateKeyPEM types.String `tfsdk:"private_key_pem"`
+	PublicKeyPEM  types.String `tfsdk:"public_key_pem"`
+	PrivateJWK    types.String `tfsdk:"private_jwk"`
+	PublicJWK     types.String `tfsdk:"public_jwk"`
+}
+
+func (r *joseKeyPairResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_key_pair"
+}
+
+func (r *joseKeyPairResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Generates an RSA, ECDSA or Ed25519 key pair, in both PEM and JWK formats. The private key is stored unencrypted in the Terraform state.",
+		Attributes:          keyPairSchema,
+	}
+}
+
+func (r *joseKeyPairResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+}
+
+func (r *joseKeyPairResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data joseKeyPairResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	privateKeyPEM, publicKeyPEM, err := generateKeyPair(data.Algorithm.ValueString(), int(data.RSABits.ValueInt64()), data.ECDSACurve.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to generate key pair", err.Error())
+		return
+	}
+
+	// The JWKs are created from the PEM key, as done by jose_jwk.  The key ID
+	// is derived from the public key when it is not set.
+	jwk := joseJwkResourceModel{
+		PrivateKey: types.StringValue(string(privateKeyPEM)),
+		Alg:        data.Alg,
+		KID:        data.KID,
+		Use:        data.Use,
+	}
+	if err := createJWK(&jwk); err != nil {
+		resp.Diagnostics.AddError("Error creating JWK", err.Error())
+		return
+	}
+
+	data.KID = jwk.KID
+	data.Alg = jwk.Alg
+	data.PrivateKeyPEM = types.StringValue(string(privateKeyPEM))
+	data.PublicKeyPEM = types.StringValue(string(publicKeyPEM))
+	data.PrivateJWK = jwk.PrivateJWK
+	data.PublicJWK = jwk.JWK
+
+	// Write logs using the tflog package
+	// Documentation: https://terraform.io/plugin/log
+	tflog.Trace(ctx, "created a resource")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *joseKeyPairResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data joseKeyPairResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *joseKeyPairResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data joseKeyPairResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *joseKeyPairResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data joseKeyPairResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
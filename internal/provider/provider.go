@@ -54,10 +54,18 @@ func (p *joseProvider) Resources(ctx context.Context) []func() resource.Resource
 		NewJoseJwkResource,
 		NewJoseJwksResource,
 		NewJoseJwtSignResource,
+		NewJoseJweEncryptResource,
+		NewJoseJwsSignResource,
+		NewJoseKeyPairResource,
 	}
 }
 
 // DataSources defines the data sources implemented in the provider.
 func (p *joseProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
-	return nil
+	return []func() datasource.DataSource{
+		NewJoseJweDecryptDataSource,
+		NewJoseJwkPemDataSource,
+		NewJoseJwtDecodeDataSource,
+		NewJoseJwtVerifyDataSource,
+	}
 }
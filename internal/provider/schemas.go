@@ -4,61 +4,157 @@
 package provider
 
 import (
+	"context"
+	"slices"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
 var (
+	// Arbitrary values forcing the replacement of the resource, like the
+	// keepers of the random provider.
+	keepersAttribute = schema.MapAttribute{
+		ElementType: types.StringType,
+		Optional:    true,
+		PlanModifiers: []planmodifier.Map{
+			mapplanmodifier.RequiresReplace(),
+		},
+		MarkdownDescription: "Arbitrary map of values that, when changed, will trigger the recreation of the resource, e.g. a deploy ID or a rotation date.",
+	}
+
 	jwkSchema = map[string]schema.Attribute{
 		"kid": schema.StringAttribute{
+			Computed: true,
 			Optional: true,
 			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.UseStateForUnknown(),
 				stringplanmodifier.RequiresReplace(),
 			},
-			Description: "Key ID.",
+			Description: "Key ID. Defaults to the RFC 7638 SHA-256 thumbprint of the key.",
 		},
 		"alg": schema.StringAttribute{
 			Computed:    true,
 			Optional:    true,
-			Description: "The algorithm used to sign the JWT - Applicable only for RSA keys. Supported values: RS256, RS384, RS512. Default to RS256",
+			Description: "The algorithm intended for use with the key, which must match the key type and use. Signing keys support RS256, RS384, RS512, PS256, PS384, PS512 (RSA), ES256, ES384, ES512 (ECDSA), ES256K (secp256k1) and EdDSA (Ed25519). Encryption keys support RSA-OAEP, RSA-OAEP-256 (RSA), ECDH-ES, ECDH-ES+A128KW, ECDH-ES+A192KW and ECDH-ES+A256KW (ECDSA, X25519 and X448). Default to RS256 for RSA signing keys, RSA-OAEP-256 or ECDH-ES+A256KW for encryption keys, and the only applicable algorithm otherwise",
 			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.UseStateForUnknown(),
 				stringplanmodifier.RequiresReplace(),
 			},
-			Default: stringdefault.StaticString("RS256"),
 			Validators: []validator.String{
 				stringvalidator.OneOf(
-					"RS256", "RS384", "RS512"),
+					jwkAlgorithms...),
 			},
 		},
 		"use": schema.StringAttribute{
 			Computed:    true,
 			Optional:    true,
-			Description: "The key usage. Supported values: sig, enc. Default to sig",
+			Description: "The key usage. Supported values: sig, enc. Default to sig, unless key_ops is set",
 			PlanModifiers: []planmodifier.String{
-				stringplanmodifier.RequiresReplace(),
+				stringplanmodifier.UseStateForUnknown(),
+				// The use is left unset with key_ops, which is not updated
+				// in place, so an unknown use is still unset.
+				stringplanmodifier.RequiresReplaceIf(
+					func(ctx context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+						resp.RequiresReplace = !req.PlanValue.IsUnknown() || !req.StateValue.IsNull()
+					},
+					"Changing the key usage requires replacement.",
+					"Changing the key usage requires replacement.",
+				),
 			},
-			Default: stringdefault.StaticString("sig"),
 			Validators: []validator.String{
 				stringvalidator.OneOf(
 					"sig", "enc",
 				),
 			},
 		},
+		"key_ops": schema.SetAttribute{
+			ElementType: types.StringType,
+			Optional:    true,
+			Description: "The operations the key is intended for (RFC 7517 key_ops). Supported values: sign, verify, encrypt, decrypt, wrapKey, unwrapKey, deriveKey, deriveBits. Must agree with use when both are set",
+			PlanModifiers: []planmodifier.Set{
+				setplanmodifier.RequiresReplace(),
+			},
+			Validators: []validator.Set{
+				setvalidator.SizeAtLeast(1),
+				setvalidator.ValueStringsAre(
+					stringvalidator.OneOf(
+						append(slices.Clone(jwkSigningKeyOps), jwkEncryptionKeyOps...)...),
+				),
+			},
+		},
 		"public_key": schema.StringAttribute{
 			Optional: true,
 			PlanModifiers: []planmodifier.String{
 				stringplanmodifier.RequiresReplace(),
 			},
-			Description: "Public key in PEM format.",
+			Description: "Public key in PEM format. X25519 and X448 keys can only be used for encryption. Conflicts with private_key. At least one of public_key, private_key or certificate must be set.",
+		},
+		"private_key": schema.StringAttribute{
+			Optional:  true,
+			Sensitive: true,
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.RequiresReplace(),
+			},
+			Validators: []validator.String{
+				stringvalidator.ConflictsWith(
+					path.MatchRelative().AtParent().AtName("public_key"),
+				),
+				stringvalidator.AtLeastOneOf(
+					path.MatchRelative().AtParent().AtName("public_key"),
+					path.MatchRelative().AtParent().AtName("certificate"),
+				),
+			},
+			Description: "Private key in PEM format. The public JWK is derived from it, and the private JWK is exported in private_jwk. Conflicts with public_key. At least one of public_key, private_key or certificate must be set.",
+		},
+		"certificate": schema.StringAttribute{
+			Optional: true,
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.RequiresReplace(),
+			},
+			Description: "X.509 certificate, or certificate chain starting with the leaf, in PEM format. Populates the x5c, x5t and x5t#S256 members. The public key is taken from the leaf certificate when neither public_key nor private_key is set, otherwise the leaf certificate must match the key.",
+		},
+		"trust_anchors": schema.StringAttribute{
+			Optional:    true,
+			Description: "Trust anchor certificates in PEM format. When set, the certificate chain must build to one of them. Only checked during plan.",
+		},
+		"certificate_expiry_window": schema.StringAttribute{
+			Optional:    true,
+			Description: "Report certificates in the certificate chain that expire within this duration, such as \"720h\". Expired certificates are always reported. Defaults to \"0s\".",
+			Validators: []validator.String{
+				isDuration(),
+			},
+		},
+		"certificate_validation": schema.StringAttribute{
+			Optional:    true,
+			Description: "Severity of the certificate chain and expiry problems found during plan. Supported values: error, warning. Default to error",
+			Validators: []validator.String{
+				stringvalidator.OneOf(
+					"error", "warning",
+				),
+			},
 		},
 		"jwk": schema.StringAttribute{ // This is a stub. Not used in this resource.
 			Computed:    true,
 			Description: "The resulting JWK Set in JSON format.",
 			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.UseStateForUnknown(),
 				stringplanmodifier.RequiresReplace(),
 			},
 		},
@@ -66,36 +162,114 @@ var (
 			Computed:    true,
 			Description: "The resulting JWK Set in JSON format.",
 			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.UseStateForUnknown(),
 				stringplanmodifier.RequiresReplace(),
 			},
 		},
+		"private_jwk": schema.StringAttribute{
+			Computed:    true,
+			Sensitive:   true,
+			Description: "The resulting private JWK in JSON format. Only set when private_key is set.",
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.UseStateForUnknown(),
+			},
+		},
+		"thumbprint_sha256": schema.StringAttribute{
+			Computed:    true,
+			Description: "The RFC 7638 SHA-256 thumbprint of the key, base64url encoded.",
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.UseStateForUnknown(),
+			},
+		},
+		"thumbprint_sha1": schema.StringAttribute{
+			Computed:    true,
+			Description: "The RFC 7638 SHA-1 thumbprint of the key, base64url encoded.",
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.UseStateForUnknown(),
+			},
+		},
+		"thumbprint_uri": schema.StringAttribute{
+			Computed:    true,
+			Description: "The RFC 9278 URI of the SHA-256 thumbprint of the key.",
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.UseStateForUnknown(),
+			},
+		},
 	}
 
 	jwtSchema = map[string]schema.Attribute{
 		"private_key": schema.StringAttribute{
-			Required:            true,
+			Optional:            true,
+			Sensitive:           true,
+			MarkdownDescription: "Private key in PEM format for signing JWT: PKCS #1 or PKCS #8 for RSA, SEC 1 or PKCS #8 for ECDSA and PKCS #8 for Ed25519 keys. Exactly one of `private_key`, `private_jwk`, `private_jwks` or `secret` must be set.",
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.RequiresReplace(),
+			},
+		},
+		"private_jwk": schema.StringAttribute{
+			Optional:            true,
+			Sensitive:           true,
+			MarkdownDescription: "Private JWK in JSON format for signing JWT. Its `alg` is used when `alg` is not set, and its `kid` when `kid` is not set. It must not be intended for encryption by its `use` or `key_ops`. Exactly one of `private_key`, `private_jwk`, `private_jwks` or `secret` must be set.",
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.RequiresReplace(),
+			},
+		},
+		"private_jwks": schema.StringAttribute{
+			Optional:            true,
+			Sensitive:           true,
+			MarkdownDescription: "Private JWK Set in JSON format, from which the signing key is selected by `kid`. Keys intended for encryption by their `use`, or whose `key_ops` do not include `sign`, are ignored. When `kid` is not set, the set must contain exactly one signing key. The selected key is used as for `private_jwk`. Exactly one of `private_key`, `private_jwk`, `private_jwks` or `secret` must be set.",
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.RequiresReplace(),
+			},
+		},
+		"private_key_passphrase": schema.StringAttribute{
+			Optional:            true,
 			Sensitive:           true,
-			MarkdownDescription: "Private key in PEM format for signing JWT.",
+			MarkdownDescription: "Passphrase of an encrypted `private_key`: an encrypted PKCS #8 key (`ENCRYPTED PRIVATE KEY`) using PBES2 with PBKDF2 and AES-CBC or 3DES-CBC, or a legacy encrypted PEM key with a `DEK-Info` header.",
 			PlanModifiers: []planmodifier.String{
 				stringplanmodifier.RequiresReplace(),
 			},
+			Validators: []validator.String{
+				stringvalidator.AlsoRequires(
+					path.MatchRelative().AtParent().AtName("private_key"),
+				),
+			},
+		},
+		"secret": schema.StringAttribute{
+			Optional:            true,
+			Sensitive:           true,
+			MarkdownDescription: "Symmetric secret for signing JWT with HMAC, encoded as set by `secret_encoding`. Must be at least 32, 48 or 64 bytes long for \"HS256\", \"HS384\" or \"HS512\" respectively. Exactly one of `private_key`, `private_jwk`, `private_jwks` or `secret` must be set.",
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.RequiresReplace(),
+			},
+		},
+		"secret_encoding": schema.StringAttribute{
+			Optional:            true,
+			MarkdownDescription: "Encoding of `secret`. Defaults to \"raw\".  Accepted values: \"raw\", \"base64\" (standard or URL-safe, with or without padding), \"jwk\" (a JWK of type \"oct\").",
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.RequiresReplace(),
+			},
+			Validators: []validator.String{
+				stringvalidator.OneOf(
+					"raw", "base64", "jwk"),
+			},
 		},
 		"alg": schema.StringAttribute{
 			Computed:            true,
 			Optional:            true,
-			MarkdownDescription: "Algorithm to use for signing JWT. Only applicable to RSA keys.Defaults to \"RS256\".  Accepted values: \"RS256\", \"384\", \"RS512\".",
+			MarkdownDescription: "Algorithm to use for signing JWT. Must match the key type: \"RS256\", \"RS384\", \"RS512\", \"PS256\", \"PS384\" or \"PS512\" for RSA keys, \"ES256\", \"ES384\", \"ES512\" or \"ES256K\" for ECDSA keys by curve, \"EdDSA\" for Ed25519 keys and \"HS256\", \"HS384\" or \"HS512\" for `secret`. RSA algorithms are ignored, with a warning, for ECDSA and EdDSA keys in `private_key`. Defaults to \"RS256\" for RSA keys, \"HS256\" for `secret` (or the `alg` of a JWK secret), the `alg` of `private_jwk` or `private_jwks` when set, and the only applicable algorithm for ECDSA and EdDSA keys.",
 			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.UseStateForUnknown(),
 				stringplanmodifier.RequiresReplace(),
 			},
-			Default: stringdefault.StaticString("RS256"),
 			Validators: []validator.String{
 				stringvalidator.OneOf(
-					"RS256", "RS384", "RS512"),
+					append(slices.Clone(jwsPrivateKeyAlgorithms), "HS256", "HS384", "HS512")...),
 			},
 		},
 		"kid": schema.StringAttribute{
 			Optional:            true,
-			MarkdownDescription: "Key ID, in the context of JWK Set, to identify the key used.",
+			MarkdownDescription: "Key ID, in the context of JWK Set, to identify the key used. Selects the key of `private_jwks`, and defaults to the `kid` of `private_jwk` or `private_jwks` in the JWT header.",
 			PlanModifiers: []planmodifier.String{
 				stringplanmodifier.RequiresReplace(),
 			},
@@ -107,10 +281,384 @@ var (
 				stringplanmodifier.RequiresReplace(),
 			},
 		},
+		"expires_in": schema.StringAttribute{
+			Optional:            true,
+			MarkdownDescription: "Set the `exp` claim to the signing time plus this duration, such as \"15m\" or \"24h\". The JWT is only signed again when the resource is replaced. Conflicts with an `exp` claim in `claims_json`.",
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.RequiresReplace(),
+			},
+			Validators: []validator.String{
+				isPositiveDuration(),
+			},
+		},
+		"not_before_offset": schema.StringAttribute{
+			Optional:            true,
+			MarkdownDescription: "Set the `nbf` claim to the signing time plus this duration, which can be negative to allow for clock skew, such as \"-30s\". Conflicts with an `nbf` claim in `claims_json`.",
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.RequiresReplace(),
+			},
+			Validators: []validator.String{
+				isDuration(),
+			},
+		},
+		"set_issued_at": schema.BoolAttribute{
+			Optional:            true,
+			MarkdownDescription: "Whether to set the `iat` claim to the signing time. Conflicts with an `iat` claim in `claims_json`. Defaults to `false`.",
+			PlanModifiers: []planmodifier.Bool{
+				boolplanmodifier.RequiresReplace(),
+			},
+		},
+		"generate_jti": schema.BoolAttribute{
+			Optional:            true,
+			MarkdownDescription: "Whether to set the `jti` claim to a random UUID. Conflicts with a `jti` claim in `claims_json`. Defaults to `false`.",
+			PlanModifiers: []planmodifier.Bool{
+				boolplanmodifier.RequiresReplace(),
+			},
+		},
+		"early_renewal": schema.StringAttribute{
+			Optional:            true,
+			MarkdownDescription: "Sign the JWT again when it expires within this duration, such as \"1h\", by planning its replacement. The JWT is always signed again once its `exp` claim has passed. Only applies to JWTs with an `exp` claim.",
+			Validators: []validator.String{
+				isDuration(),
+			},
+		},
+		"keepers": keepersAttribute,
+		"ready_for_renewal": schema.BoolAttribute{
+			Computed:            true,
+			MarkdownDescription: "Whether the JWT has expired or expires within `early_renewal`, in which case the resource is replaced on the next apply.",
+			PlanModifiers: []planmodifier.Bool{
+				boolplanmodifier.UseStateForUnknown(),
+			},
+		},
+		"issued_at": schema.StringAttribute{
+			Computed:            true,
+			MarkdownDescription: "The `iat` claim set by `set_issued_at`, in RFC 3339 format.",
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.UseStateForUnknown(),
+			},
+		},
+		"not_before": schema.StringAttribute{
+			Computed:            true,
+			MarkdownDescription: "The `nbf` claim set by `not_before_offset`, in RFC 3339 format.",
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.UseStateForUnknown(),
+			},
+		},
+		"expires_at": schema.StringAttribute{
+			Computed:            true,
+			MarkdownDescription: "The `exp` claim set by `expires_in`, in RFC 3339 format.",
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.UseStateForUnknown(),
+			},
+		},
+		"jti": schema.StringAttribute{
+			Computed:            true,
+			MarkdownDescription: "The `jti` claim set by `generate_jti`.",
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.UseStateForUnknown(),
+			},
+		},
 		"jwt": schema.StringAttribute{
 			Computed:            true,
 			Sensitive:           true,
 			MarkdownDescription: "The resulting signed JWT in Base64url format.",
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.UseStateForUnknown(),
+			},
+		},
+	}
+
+	jweEncryptSchema = map[string]schema.Attribute{
+		"plaintext": schema.StringAttribute{
+			Required:            true,
+			Sensitive:           true,
+			MarkdownDescription: "Plaintext to be encrypted. Can be a nested JWT, in which case `cty` should be set to `\"JWT\"`.",
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.RequiresReplace(),
+			},
+		},
+		"public_key": schema.StringAttribute{
+			Optional:            true,
+			MarkdownDescription: "Recipient public key in PEM format. Exactly one of `public_key` or `public_jwk` must be set.",
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.RequiresReplace(),
+			},
+		},
+		"public_jwk": schema.StringAttribute{
+			Optional:            true,
+			MarkdownDescription: "Recipient public key as a JWK in JSON format. Exactly one of `public_key` or `public_jwk` must be set.",
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.RequiresReplace(),
+			},
+		},
+		"alg": schema.StringAttribute{
+			Computed:            true,
+			Optional:            true,
+			MarkdownDescription: "Key management algorithm. Accepted values: \"RSA-OAEP\", \"RSA-OAEP-256\" for RSA keys, and \"ECDH-ES\", \"ECDH-ES+A128KW\", \"ECDH-ES+A192KW\", \"ECDH-ES+A256KW\" for ECDSA keys. Defaults to \"RSA-OAEP-256\" or \"ECDH-ES+A256KW\" depending on the key type.",
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.RequiresReplace(),
+			},
+			Validators: []validator.String{
+				stringvalidator.OneOf(
+					append(slices.Clone(jweRSAKeyAlgorithms), jweECDHKeyAlgorithms...)...),
+			},
+		},
+		"enc": schema.StringAttribute{
+			Computed:            true,
+			Optional:            true,
+			MarkdownDescription: "Content encryption algorithm. Defaults to \"A256GCM\".  Accepted values: \"A128GCM\", \"A192GCM\", \"A256GCM\", \"A128CBC-HS256\", \"A192CBC-HS384\", \"A256CBC-HS512\".",
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.RequiresReplace(),
+			},
+			Default: stringdefault.StaticString("A256GCM"),
+			Validators: []validator.String{
+				stringvalidator.OneOf(jweContentEncryptions...),
+			},
+		},
+		"kid": schema.StringAttribute{
+			Optional:            true,
+			MarkdownDescription: "Key ID of the recipient key. Defaults to the `kid` of `public_jwk` when set.",
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.RequiresReplace(),
+			},
+		},
+		"cty": schema.StringAttribute{
+			Optional:            true,
+			MarkdownDescription: "Content type header. Set to \"JWT\" when the plaintext is a nested JWT.",
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.RequiresReplace(),
+			},
+		},
+		"jwe": schema.StringAttribute{
+			Computed:            true,
+			MarkdownDescription: "The resulting JWE in compact serialization.",
+		},
+	}
+
+	jwsSchema = map[string]schema.Attribute{
+		"payload": schema.StringAttribute{
+			Optional:            true,
+			MarkdownDescription: "Payload to be signed, as a string. Exactly one of `payload` or `payload_base64` must be set.",
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.RequiresReplace(),
+			},
+		},
+		"payload_base64": schema.StringAttribute{
+			Optional:            true,
+			MarkdownDescription: "Payload to be signed, as base64 encoded bytes. Use it for binary content. Exactly one of `payload` or `payload_base64` must be set.",
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.RequiresReplace(),
+			},
+		},
+		"private_key": schema.StringAttribute{
+			Optional:            true,
+			Sensitive:           true,
+			MarkdownDescription: "Private key in PEM format for signing the payload. Exactly one of `private_key` or `signatures` must be set.",
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.RequiresReplace(),
+			},
+		},
+		"alg": schema.StringAttribute{
+			Computed:            true,
+			Optional:            true,
+			MarkdownDescription: "Algorithm to use for signing. Must match the key type: \"RS256\", \"RS384\", \"RS512\", \"PS256\", \"PS384\" or \"PS512\" for RSA keys, \"ES256\", \"ES384\", \"ES512\" or \"ES256K\" for ECDSA keys by curve and \"EdDSA\" for Ed25519 keys. RSA algorithms are ignored, with a warning, for ECDSA and EdDSA keys. Defaults to \"RS256\" for RSA keys and the only applicable algorithm for ECDSA and EdDSA keys. Only applicable to `private_key`.",
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.UseStateForUnknown(),
+				stringplanmodifier.RequiresReplace(),
+			},
+			Validators: []validator.String{
+				stringvalidator.OneOf(
+					jwsPrivateKeyAlgorithms...),
+			},
+		},
+		"kid": schema.StringAttribute{
+			Optional:            true,
+			MarkdownDescription: "Key ID (`kid`) protected header. Only applicable to `private_key`.",
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.RequiresReplace(),
+			},
+		},
+		"signatures": schema.ListNestedAttribute{
+			Optional:            true,
+			MarkdownDescription: "Signatures to create over the payload, each with its own key and headers. Use it to sign with both the old and new key during key rotation. More than one signature requires `json` serialization. Exactly one of `private_key` or `signatures` must be set.",
+			NestedObject: schema.NestedAttributeObject{
+				Attributes: map[string]schema.Attribute{
+					"private_key": schema.StringAttribute{
+						Required:            true,
+						Sensitive:           true,
+						MarkdownDescription: "Private key in PEM format for this signature.",
+					},
+					"alg": schema.StringAttribute{
+						Computed:            true,
+						Optional:            true,
+						MarkdownDescription: "Algorithm to use for this signature. Accepts the same values as `alg`, and defaults to \"RS256\" for RSA keys and the only applicable algorithm for ECDSA and EdDSA keys.",
+						PlanModifiers: []planmodifier.String{
+							stringplanmodifier.UseStateForUnknown(),
+						},
+						Validators: []validator.String{
+							stringvalidator.OneOf(
+								jwsPrivateKeyAlgorithms...),
+						},
+					},
+					"kid": schema.StringAttribute{
+						Optional:            true,
+						MarkdownDescription: "Key ID (`kid`) protected header of this signature.",
+					},
+					"protected_headers_json": schema.StringAttribute{
+						Optional:            true,
+						MarkdownDescription: "Additional protected headers of this signature, as a JSON object. Extensions listed in a `crit` header must be set here.",
+					},
+					"unprotected_headers_json": schema.StringAttribute{
+						Optional:            true,
+						MarkdownDescription: "Unprotected headers of this signature, as a JSON object. Not supported by `compact` serialization.",
+					},
+				},
+			},
+			PlanModifiers: []planmodifier.List{
+				listplanmodifier.RequiresReplace(),
+			},
+			Validators: []validator.List{
+				listvalidator.SizeAtLeast(1),
+			},
+		},
+		"serialization": schema.StringAttribute{
+			Computed:            true,
+			Optional:            true,
+			MarkdownDescription: "Serialization of the resulting JWS. Defaults to \"compact\".  Accepted values: \"compact\", \"json\" (general JSON serialization), \"flattened\" (flattened JSON serialization).",
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.RequiresReplace(),
+			},
+			Default: stringdefault.StaticString("compact"),
+			Validators: []validator.String{
+				stringvalidator.OneOf(
+					"compact", "json", "flattened"),
+			},
+		},
+		"detached": schema.BoolAttribute{
+			Computed:            true,
+			Optional:            true,
+			MarkdownDescription: "Whether to leave the payload out of the JWS, for a detached signature. In compact serialization the payload segment is empty. Defaults to `false`.",
+			PlanModifiers: []planmodifier.Bool{
+				boolplanmodifier.RequiresReplace(),
+			},
+			Default: booldefault.StaticBool(false),
+		},
+		"unencoded_payload": schema.BoolAttribute{
+			Computed:            true,
+			Optional:            true,
+			MarkdownDescription: "Whether to sign the payload as is rather than base64url encoded (RFC 7797). Adds the `b64` header, set to `false`, and lists it in the `crit` header of every signature. An attached payload must not contain `.` characters in compact serialization. Defaults to `false`.",
+			PlanModifiers: []planmodifier.Bool{
+				boolplanmodifier.RequiresReplace(),
+			},
+			Default: booldefault.StaticBool(false),
+		},
+		"typ": schema.StringAttribute{
+			Optional:            true,
+			MarkdownDescription: "Type (`typ`) protected header, added to every signature.",
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.RequiresReplace(),
+			},
+		},
+		"cty": schema.StringAttribute{
+			Optional:            true,
+			MarkdownDescription: "Content type (`cty`) protected header, added to every signature.",
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.RequiresReplace(),
+			},
+		},
+		"jws": schema.StringAttribute{
+			Computed:            true,
+			MarkdownDescription: "The resulting JWS in the requested serialization.",
+		},
+	}
+
+	keyPairSchema = map[string]schema.Attribute{
+		"algorithm": schema.StringAttribute{
+			Required:            true,
+			MarkdownDescription: "Algorithm of the generated key. Accepted values: \"RSA\", \"ECDSA\", \"Ed25519\".",
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.RequiresReplace(),
+			},
+			Validators: []validator.String{
+				stringvalidator.OneOf(
+					"RSA", "ECDSA", "Ed25519"),
+			},
+		},
+		"rsa_bits": schema.Int64Attribute{
+			Computed:            true,
+			Optional:            true,
+			MarkdownDescription: "Size of the generated RSA key, in bits. Only applicable to RSA keys. Defaults to `2048`.",
+			PlanModifiers: []planmodifier.Int64{
+				int64planmodifier.RequiresReplace(),
+			},
+			Default: int64default.StaticInt64(2048),
+			Validators: []validator.Int64{
+				int64validator.AtLeast(2048),
+			},
+		},
+		"ecdsa_curve": schema.StringAttribute{
+			Computed:            true,
+			Optional:            true,
+			MarkdownDescription: "Elliptic curve of the generated ECDSA key. Only applicable to ECDSA keys. Defaults to \"P-256\".  Accepted values: \"P-256\", \"P-384\", \"P-521\".",
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.RequiresReplace(),
+			},
+			Default: stringdefault.StaticString("P-256"),
+			Validators: []validator.String{
+				stringvalidator.OneOf(
+					"P-256", "P-384", "P-521"),
+			},
+		},
+		"alg": schema.StringAttribute{
+			Computed:            true,
+			Optional:            true,
+			MarkdownDescription: "Algorithm (`alg`) of the JWKs, which must match the key type and `use`, as for `jose_jwk`. Defaults to \"RS256\" for RSA signing keys, \"RSA-OAEP-256\" or \"ECDH-ES+A256KW\" for encryption keys, and the only applicable algorithm otherwise.",
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.RequiresReplace(),
+			},
+			Validators: []validator.String{
+				stringvalidator.OneOf(
+					jwkAlgorithms...),
+			},
+		},
+		"kid": schema.StringAttribute{
+			Computed:            true,
+			Optional:            true,
+			MarkdownDescription: "Key ID (`kid`) of the JWKs. Defaults to the RFC 7638 SHA-256 thumbprint of the public key.",
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.RequiresReplace(),
+			},
+		},
+		"use": schema.StringAttribute{
+			Computed:            true,
+			Optional:            true,
+			MarkdownDescription: "Key usage (`use`) of the JWKs. Defaults to \"sig\".  Accepted values: \"sig\", \"enc\".",
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.RequiresReplace(),
+			},
+			Default: stringdefault.StaticString("sig"),
+			Validators: []validator.String{
+				stringvalidator.OneOf(
+					"sig", "enc"),
+			},
+		},
+		"private_key_pem": schema.StringAttribute{
+			Computed:            true,
+			Sensitive:           true,
+			MarkdownDescription: "The generated private key in PEM format: PKCS #1 for RSA, SEC 1 for ECDSA and PKCS #8 for Ed25519 keys.",
+		},
+		"public_key_pem": schema.StringAttribute{
+			Computed:            true,
+			MarkdownDescription: "The public key in PEM (PKIX) format.",
+		},
+		"private_jwk": schema.StringAttribute{
+			Computed:            true,
+			Sensitive:           true,
+			MarkdownDescription: "The private JWK in JSON format.",
+		},
+		"public_jwk": schema.StringAttribute{
+			Computed:            true,
+			MarkdownDescription: "The public JWK in JSON format.",
 		},
 	}
 )
@@ -0,0 +1,83 @@
+// Copyright (c) Tze Liang
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Decode a JSON document into a Terraform value, in the same way as the
+// jsondecode function: objects become object values, arrays become tuple
+// values and numbers keep their full precision.
+func jsonToValue(ctx context.Context, data []byte) (attr.Value, error) {
+	var v interface{}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	return toValue(ctx, v)
+}
+
+func toValue(ctx context.Context, v interface{}) (attr.Value, error) {
+	switch v := v.(type) {
+	case nil:
+		return types.DynamicNull(), nil
+	case bool:
+		return types.BoolValue(v), nil
+	case string:
+		return types.StringValue(v), nil
+	case json.Number:
+		// Terraform numbers are arbitrary precision, 512 bits matches the
+		// precision used by Terraform itself.
+		f, _, err := big.ParseFloat(v.String(), 10, 512, big.ToNearestEven)
+		if err != nil {
+			return nil, err
+		}
+		return types.NumberValue(f), nil
+	case []interface{}:
+		elemTypes := make([]attr.Type, len(v))
+		elems := make([]attr.Value, len(v))
+		for i, item := range v {
+			elem, err := toValue(ctx, item)
+			if err != nil {
+				return nil, err
+			}
+			elemTypes[i] = elem.Type(ctx)
+			elems[i] = elem
+		}
+		value, diags := types.TupleValue(elemTypes, elems)
+		if diags.HasError() {
+			return nil, fmt.Errorf("could not convert JSON array: %v", diags)
+		}
+		return value, nil
+	case map[string]interface{}:
+		attrTypes := make(map[string]attr.Type, len(v))
+		attrs := make(map[string]attr.Value, len(v))
+		for name, item := range v {
+			elem, err := toValue(ctx, item)
+			if err != nil {
+				return nil, err
+			}
+			attrTypes[name] = elem.Type(ctx)
+			attrs[name] = elem
+		}
+		value, diags := types.ObjectValue(attrTypes, attrs)
+		if diags.HasError() {
+			return nil, fmt.Errorf("could not convert JSON object: %v", diags)
+		}
+		return value, nil
+	default:
+		return nil, fmt.Errorf("unsupported JSON value of type %T", v)
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright (c) Tze Liang
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/go-jose/go-jose/v4"
+)
+
+// Key management algorithms supported for JWE, grouped by the type of the
+// recipient key.  RSA1_5 is deliberately left out as it is considered unsafe.
+var (
+	jweRSAKeyAlgorithms = []string{
+		"RSA-OAEP", "RSA-OAEP-256",
+	}
+	jweECDHKeyAlgorithms = []string{
+		"ECDH-ES", "ECDH-ES+A128KW", "ECDH-ES+A192KW", "ECDH-ES+A256KW",
+	}
+	jweContentEncryptions = []string{
+		"A128GCM", "A192GCM", "A256GCM",
+		"A128CBC-HS256", "A192CBC-HS384", "A256CBC-HS512",
+	}
+)
+
+// Return the default key management algorithm for the given recipient key,
+// or validate the configured one against the key type.
+func jweKeyAlgorithm(key crypto.PublicKey, alg string) (string, error) {
+	var supported []string
+
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		supported = jweRSAKeyAlgorithms
+		if alg == "" {
+			alg = "RSA-OAEP-256"
+		}
+	case *ecdsa.PublicKey:
+		if isSecp256k1(k.Curve) {
+			return "", errors.New("secp256k1 keys can only be used for signing")
+		}
+		supported = jweECDHKeyAlgorithms
+		if alg == "" {
+			alg = "ECDH-ES+A256KW"
+		}
+	case ed25519.PublicKey:
+		return "", errors.New("ed25519 keys can only be used for signing")
+	default:
+		return "", errors.New("unsupported public key type")
+	}
+
+	if !slices.Contains(supported, alg) {
+		return "", fmt.Errorf("key management algorithm %q cannot be used with this key type, expected one of: %v", alg, supported)
+	}
+
+	return alg, nil
+}
+
+// Encrypt plaintext to a single recipient and return the JWE compact
+// serialization.
+func encryptJWE(plaintext []byte, key crypto.PublicKey, alg, enc, kid, cty string) (string, error) {
+	recipient := jose.Recipient{
+		Algorithm: jose.KeyAlgorithm(alg),
+		Key:       key,
+		KeyID:     kid,
+	}
+
+	opts := &jose.EncrypterOptions{}
+	if cty != "" {
+		opts = opts.WithContentType(jose.ContentType(cty))
+	}
+
+	encrypter, err := jose.NewEncrypter(jose.ContentEncryption(enc), recipient, opts)
+	if err != nil {
+		return "", err
+	}
+
+	object, err := encrypter.Encrypt(plaintext)
+	if err != nil {
+		return "", err
+	}
+
+	return object.CompactSerialize()
+}
+
+// Decrypt a JWE in either compact or JSON serialization.  When the JWE has
+// several recipients, the first one that the key can decrypt is used.  The
+// merged header of that recipient is returned along with the plaintext.
+func decryptJWE(token string, key PrivateKey, kid string) ([]byte, *jose.Header, error) {
+	var keyAlgorithms []jose.KeyAlgorithm
+	var contentEncryptions []jose.ContentEncryption
+
+	for _, alg := range append(slices.Clone(jweRSAKeyAlgorithms), jweECDHKeyAlgorithms...) {
+		keyAlgorithms = append(keyAlgorithms, jose.KeyAlgorithm(alg))
+	}
+	for _, enc := range jweContentEncryptions {
+		contentEncryptions = append(contentEncryptions, jose.ContentEncryption(enc))
+	}
+
+	object, err := jose.ParseEncrypted(token, keyAlgorithms, contentEncryptions)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	_, header, plaintext, err := object.DecryptMulti(key.cryptoKey())
+	if err != nil {
+		// Explain the most likely cause using the header of the first
+		// recipient, which is the only one for compact serialization.
+		if kid != "" && object.Header.KeyID != "" && object.Header.KeyID != kid {
+			return nil, nil, fmt.Errorf("JWE was encrypted for key ID %q, but the private key has key ID %q", object.Header.KeyID, kid)
+		}
+		if signer, ok := key.cryptoKey().(crypto.Signer); ok {
+			if _, algErr := jweKeyAlgorithm(signer.Public(), object.Header.Algorithm); algErr != nil {
+				return nil, nil, fmt.Errorf("JWE cannot be decrypted with this private key: %w", algErr)
+			}
+		}
+		return nil, nil, err
+	}
+
+	if kid != "" && header.KeyID != "" && header.KeyID != kid {
+		return nil, nil, fmt.Errorf("JWE was encrypted for key ID %q, but the private key has key ID %q", header.KeyID, kid)
+	}
+
+	return plaintext, &header, nil
+}
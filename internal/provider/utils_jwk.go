@@ -4,69 +4,587 @@
 package provider
 
 import (
+	"crypto"
 	"crypto/ecdsa"
-	"crypto/ed25519"
-	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
 	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
 	"encoding/pem"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
 
 	"github.com/go-jose/go-jose/v4"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
-// Create JWK.
-func createJWK(data joseJwkResourceModel) ([]byte, error) {
+// Create JWK and save it into the computed attributes of the model.  When the
+// key is given as a private key, its private JWK is saved as well.  When no
+// key ID is set, it is derived from the SHA-256 thumbprint of the key.
+func createJWK(data *joseJwkResourceModel) error {
 	var (
-		bitLength int
-		err       error
-		jwk       jose.JSONWebKey
+		err        error
+		jwk        jose.JSONWebKey
+		privateKey PrivateKey
+		pubKey     crypto.PublicKey
 	)
 
-	// Parse the PEM-encoded public key
-	block, _ := pem.Decode([]byte(data.PublicKey.ValueString()))
-	if block == nil {
-		return nil, err
+	if data.PrivateKey.ValueString() != "" {
+		privateKey, err = parsePrivateKey([]byte(data.PrivateKey.ValueString()), data.Alg)
+		if err != nil {
+			return err
+		}
+		signer, ok := privateKey.cryptoKey().(crypto.Signer)
+		if !ok {
+			return errors.New("unsupported private key type")
+		}
+		pubKey = signer.Public()
+	} else if data.PublicKey.ValueString() != "" {
+		pubKey, err = parsePublicKey([]byte(data.PublicKey.ValueString()))
+		if err != nil {
+			return err
+		}
+	}
+
+	// The certificate members are populated from the chain, whose leaf
+	// provides the public key when no key is given.
+	if data.Certificate.ValueString() != "" {
+		certs, err := parseCertificateChain([]byte(data.Certificate.ValueString()))
+		if err != nil {
+			return err
+		}
+		if pubKey == nil {
+			pubKey = certificatePublicKey(certs[0])
+		} else if !publicKeysEqual(pubKey, certificatePublicKey(certs[0])) {
+			return errors.New("the leaf certificate does not match the key")
+		}
+
+		x5tSHA1 := sha1.Sum(certs[0].Raw)
+		x5tSHA256 := sha256.Sum256(certs[0].Raw)
+		jwk.Certificates = certs
+		jwk.CertificateThumbprintSHA1 = x5tSHA1[:]
+		jwk.CertificateThumbprintSHA256 = x5tSHA256[:]
+	}
+	if pubKey == nil {
+		return errors.New("one of public_key, private_key or certificate must be set")
 	}
+	jwk.Key = pubKey
 
-	pubKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+	keyOps := jwkKeyOps(data.KeyOps)
+	use, err := jwkKeyOpsUse(data.Use.ValueString(), keyOps)
 	if err != nil {
-		return nil, err
+		return err
+	}
+	jwk.Algorithm, err = jwkAlgorithm(pubKey, data.Alg.ValueString(), use)
+	if err != nil {
+		return err
+	}
+	// Without key_ops, the use is always set and follows the algorithm.  With
+	// key_ops, it is only set when configured.
+	jwk.Use = data.Use.ValueString()
+	if len(keyOps) == 0 && jwk.Use == "" {
+		jwk.Use = "sig"
+		if isKeyManagementAlgorithm(jwk.Algorithm) {
+			jwk.Use = "enc"
+		}
+	}
+
+	thumbprintSHA256, err := jwkThumbprint(pubKey, crypto.SHA256)
+	if err != nil {
+		return err
+	}
+	thumbprintSHA1, err := jwkThumbprint(pubKey, crypto.SHA1)
+	if err != nil {
+		return err
+	}
+
+	if data.KID.ValueString() != "" {
+		jwk.KeyID = data.KID.ValueString()
 	} else {
-		jwk.Key = pubKey
+		jwk.KeyID = thumbprintSHA256
+	}
+
+	jwkJSON, err := marshalJWK(jwk, keyOps)
+	if err != nil {
+		return err
 	}
 
-	switch k := pubKey.(type) {
-	case *rsa.PublicKey:
-		if data.Alg.ValueString() != "" {
-			jwk.Algorithm = data.Alg.ValueString()
+	data.KID = types.StringValue(jwk.KeyID)
+	data.Alg = types.StringValue(jwk.Algorithm)
+	data.Use = types.StringNull()
+	if jwk.Use != "" {
+		data.Use = types.StringValue(jwk.Use)
+	}
+	data.JWK = types.StringValue(string(jwkJSON))
+	data.JWKBase64 = types.StringValue(base64.StdEncoding.EncodeToString(jwkJSON))
+	data.ThumbprintSHA256 = types.StringValue(thumbprintSHA256)
+	data.ThumbprintSHA1 = types.StringValue(thumbprintSHA1)
+	data.ThumbprintURI = types.StringValue(jwkThumbprintURIPrefix + thumbprintSHA256)
+	data.PrivateJWK = types.StringNull()
+
+	if privateKey != nil {
+		// The private JWK shares every member with the public one.
+		jwk.Key = privateKey.cryptoKey()
+		privateJWKJSON, err := marshalJWK(jwk, keyOps)
+		if err != nil {
+			return err
 		}
+		data.PrivateJWK = types.StringValue(string(privateJWKJSON))
+	}
+
+	return nil
+}
+
+// Save the thumbprints of a JWK created before they were computed, from the
+// JWK of the model.  The model is left unchanged when they are already set.
+func updateJWKThumbprints(data *joseJwkResourceModel) error {
+	if !data.ThumbprintSHA256.IsNull() || data.JWK.ValueString() == "" {
+		return nil
+	}
+
+	jwk, err := parsePublicJWK([]byte(data.JWK.ValueString()))
+	if err != nil {
+		return err
+	}
+	thumbprintSHA256, err := jwkThumbprint(jwk.Key, crypto.SHA256)
+	if err != nil {
+		return err
+	}
+	thumbprintSHA1, err := jwkThumbprint(jwk.Key, crypto.SHA1)
+	if err != nil {
+		return err
+	}
+
+	data.ThumbprintSHA256 = types.StringValue(thumbprintSHA256)
+	data.ThumbprintSHA1 = types.StringValue(thumbprintSHA1)
+	data.ThumbprintURI = types.StringValue(jwkThumbprintURIPrefix + thumbprintSHA256)
+
+	return nil
+}
+
+// Create the JWK of the model again with the default algorithm of its key
+// when its algorithm cannot be used with the key, such as the "RS256" default
+// given to ECDSA and Ed25519 keys by earlier versions.  The model is left
+// unchanged otherwise.
+func updateJWKAlgorithm(data *joseJwkResourceModel) error {
+	if data.Alg.ValueString() == "" {
+		return nil
+	}
+
+	pubKey := configuredPublicKey(*data)
+	if pubKey == nil {
+		return nil
+	}
+	use, err := jwkKeyOpsUse(data.Use.ValueString(), jwkKeyOps(data.KeyOps))
+	if err != nil {
+		return nil
+	}
+	if _, err := jwkAlgorithm(pubKey, data.Alg.ValueString(), use); err == nil {
+		return nil
+	}
+
+	data.Alg = types.StringNull()
+	return createJWK(data)
+}
+
+// Key operations (RFC 7517 section 4.3) grouped by the use they belong to.
+var (
+	jwkSigningKeyOps = []string{
+		"sign", "verify",
+	}
+	jwkEncryptionKeyOps = []string{
+		"encrypt", "decrypt", "wrapKey", "unwrapKey", "deriveKey", "deriveBits",
+	}
+)
+
+// Return the key operations of a key_ops set, leaving out unknown and null
+// values.
+func jwkKeyOps(set types.Set) []string {
+	keyOps := make([]string, 0, len(set.Elements()))
+	for _, element := range set.Elements() {
+		if value, ok := element.(types.String); ok && !value.IsNull() && !value.IsUnknown() {
+			keyOps = append(keyOps, value.ValueString())
+		}
+	}
+	slices.Sort(keyOps)
+
+	return keyOps
+}
+
+// Return the use of a JWK, derived from its key operations when use is not
+// set.  The use and the key operations must not contradict each other (RFC
+// 7517 section 4.3).  The use is empty when neither is set, or when the key
+// operations belong to both uses.
+func jwkKeyOpsUse(use string, keyOps []string) (string, error) {
+	var signing, encryption bool
+	for _, op := range keyOps {
+		signing = signing || slices.Contains(jwkSigningKeyOps, op)
+		encryption = encryption || slices.Contains(jwkEncryptionKeyOps, op)
+	}
+
+	switch {
+	case use == "sig" && encryption:
+		return "", errors.New(`key_ops contains encryption operations, which contradict use = "sig"`)
+	case use == "enc" && signing:
+		return "", errors.New(`key_ops contains signing operations, which contradict use = "enc"`)
+	case use != "":
+		return use, nil
+	case signing && !encryption:
+		return "sig", nil
+	case encryption && !signing:
+		return "enc", nil
+	default:
+		return "", nil
+	}
+}
+
+// Marshal a JWK into JSON, with its key operations.  The key_ops member is not
+// supported by go-jose, so it is added to the JSON object as the first member.
+func marshalJWK(jwk jose.JSONWebKey, keyOps []string) ([]byte, error) {
+	var (
+		jwkJSON []byte
+		err     error
+	)
+	switch k := jwk.Key.(type) {
+	case xdhPublicKey:
+		jwkJSON, err = marshalJWKMembers(jwk, "OKP", k.Curve, k.X, nil, nil)
 	case *ecdsa.PublicKey:
-		bitLength = k.Curve.Params().BitSize
-		if bitLength == 256 {
-			jwk.Algorithm = "ES256"
-		} else if bitLength == 384 {
-			jwk.Algorithm = "ES384"
-		} else if bitLength == 521 {
-			jwk.Algorithm = "ES512"
+		if isSecp256k1(k.Curve) {
+			jwkJSON, err = marshalJWKMembers(jwk, "EC", "secp256k1", padScalar(k.X), padScalar(k.Y), nil)
 		} else {
-			return nil, err
+			jwkJSON, err = jwk.MarshalJSON()
+		}
+	case *ecdsa.PrivateKey:
+		if isSecp256k1(k.Curve) {
+			jwkJSON, err = marshalJWKMembers(jwk, "EC", "secp256k1", padScalar(k.X), padScalar(k.Y), padScalar(k.D))
+		} else {
+			jwkJSON, err = jwk.MarshalJSON()
 		}
-	case ed25519.PublicKey:
-		jwk.Algorithm = "EdDSA"
 	default:
+		jwkJSON, err = jwk.MarshalJSON()
+	}
+	if err != nil || len(keyOps) == 0 {
+		return jwkJSON, err
+	}
+
+	keyOpsJSON, err := json.Marshal(keyOps)
+	if err != nil {
 		return nil, err
 	}
 
-	if data.KID.ValueString() != "" {
-		jwk.KeyID = data.KID.ValueString()
+	return append([]byte(`{"key_ops":`+string(keyOpsJSON)+","), jwkJSON[1:]...), nil
+}
+
+// Marshal the JWK of a key that go-jose does not support, XDH (RFC 8037) or
+// secp256k1 (RFC 8812), with the members in the same order as go-jose.  The
+// key members are given already encoded; y and d are left out when nil.
+func marshalJWKMembers(jwk jose.JSONWebKey, kty, crv string, x, y, d []byte) ([]byte, error) {
+	raw := struct {
+		Use                         string   `json:"use,omitempty"`
+		Kty                         string   `json:"kty"`
+		KeyID                       string   `json:"kid,omitempty"`
+		Crv                         string   `json:"crv"`
+		Algorithm                   string   `json:"alg,omitempty"`
+		X                           string   `json:"x"`
+		Y                           string   `json:"y,omitempty"`
+		D                           string   `json:"d,omitempty"`
+		Certificates                []string `json:"x5c,omitempty"`
+		CertificateThumbprintSHA1   string   `json:"x5t,omitempty"`
+		CertificateThumbprintSHA256 string   `json:"x5t#S256,omitempty"`
+	}{
+		Use:                         jwk.Use,
+		Kty:                         kty,
+		KeyID:                       jwk.KeyID,
+		Crv:                         crv,
+		Algorithm:                   jwk.Algorithm,
+		X:                           base64.RawURLEncoding.EncodeToString(x),
+		Y:                           base64.RawURLEncoding.EncodeToString(y),
+		D:                           base64.RawURLEncoding.EncodeToString(d),
+		CertificateThumbprintSHA1:   base64.RawURLEncoding.EncodeToString(jwk.CertificateThumbprintSHA1),
+		CertificateThumbprintSHA256: base64.RawURLEncoding.EncodeToString(jwk.CertificateThumbprintSHA256),
+	}
+	for _, cert := range jwk.Certificates {
+		raw.Certificates = append(raw.Certificates, base64.StdEncoding.EncodeToString(cert.Raw))
+	}
+
+	return json.Marshal(raw)
+}
+
+// Algorithms accepted for the "alg" member of a JWK: the signing algorithms
+// for use = "sig" and the key management algorithms for use = "enc".
+var jwkAlgorithms = append(append(slices.Clone(jwsPrivateKeyAlgorithms),
+	jweRSAKeyAlgorithms...),
+	jweECDHKeyAlgorithms...)
+
+// Return the default algorithm of a JWK for its key type and use, or
+// validate the configured one.  Signing keys default to the first signing
+// algorithm of the key, and encryption keys to the key management algorithm
+// used by jose_jwe_encrypt.  Without a use, the algorithm selects it.
+func jwkAlgorithm(key crypto.PublicKey, alg string, use string) (string, error) {
+	isKeyManagement := isKeyManagementAlgorithm(alg)
+
+	// XDH keys are for key agreement only.
+	if k, ok := key.(xdhPublicKey); ok {
+		if use == "sig" || (alg != "" && !isKeyManagement) {
+			return "", fmt.Errorf("%s keys can only be used for encryption", k.Curve)
+		}
+		if alg == "" {
+			return "ECDH-ES+A256KW", nil
+		}
+		if !slices.Contains(jweECDHKeyAlgorithms, alg) {
+			return "", fmt.Errorf("key management algorithm %q cannot be used with this key type, expected one of: %v", alg, jweECDHKeyAlgorithms)
+		}
+		return alg, nil
 	}
-	if data.Use.ValueString() != "" {
-		jwk.Use = data.Use.ValueString()
+
+	if use == "enc" || (use == "" && isKeyManagement) {
+		if alg != "" && !isKeyManagement {
+			return "", fmt.Errorf("signing algorithm %q cannot be used with an encryption key", alg)
+		}
+		return jweKeyAlgorithm(key, alg)
 	}
 
-	jwkJSON, err := jwk.MarshalJSON()
+	if isKeyManagement {
+		return "", fmt.Errorf("key management algorithm %q can only be used with use = \"enc\"", alg)
+	}
+	supported, err := verificationAlgorithms(key)
 	if err != nil {
+		return "", err
+	}
+	if alg == "" {
+		return supported[0], nil
+	}
+	if !slices.Contains(supported, alg) {
+		return "", fmt.Errorf("signing algorithm %q cannot be used with this key type, expected one of: %v", alg, supported)
+	}
+
+	return alg, nil
+}
+
+// Report whether alg is a JWE key management algorithm.
+func isKeyManagementAlgorithm(alg string) bool {
+	return slices.Contains(jweRSAKeyAlgorithms, alg) || slices.Contains(jweECDHKeyAlgorithms, alg)
+}
+
+// Prefix of the RFC 9278 JWK thumbprint URI for the SHA-256 thumbprint.
+const jwkThumbprintURIPrefix = "urn:ietf:params:oauth:jwk-thumbprint:sha-256:"
+
+// Compute the RFC 7638 thumbprint of a public key, base64url encoded.
+func jwkThumbprint(key crypto.PublicKey, hash crypto.Hash) (string, error) {
+	// The required members of the key, in lexicographic order (RFC 7638
+	// section 3.2).
+	switch k := key.(type) {
+	case xdhPublicKey:
+		h := hash.New()
+		fmt.Fprintf(h, `{"crv":%q,"kty":"OKP","x":%q}`, k.Curve, base64.RawURLEncoding.EncodeToString(k.X))
+		return base64.RawURLEncoding.EncodeToString(h.Sum(nil)), nil
+	case *ecdsa.PublicKey:
+		if isSecp256k1(k.Curve) {
+			h := hash.New()
+			fmt.Fprintf(h, `{"crv":"secp256k1","kty":"EC","x":%q,"y":%q}`,
+				base64.RawURLEncoding.EncodeToString(padScalar(k.X)), base64.RawURLEncoding.EncodeToString(padScalar(k.Y)))
+			return base64.RawURLEncoding.EncodeToString(h.Sum(nil)), nil
+		}
+	}
+
+	thumbprint, err := (&jose.JSONWebKey{Key: key}).Thumbprint(hash)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(thumbprint), nil
+}
+
+// Parse a PEM-encoded public key (PKIX).
+func parsePublicKey(key []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(key)
+	if block == nil {
+		return nil, errors.New("failed to parse PEM block containing the key")
+	}
+
+	xdhKey, err := parseXDHPublicKey(block.Bytes)
+	if err != nil || xdhKey != nil {
+		return xdhKey, err
+	}
+	secp256k1Key, err := parseSecp256k1PublicKey(block.Bytes)
+	if err != nil || secp256k1Key != nil {
+		return secp256k1Key, err
+	}
+
+	return x509.ParsePKIXPublicKey(block.Bytes)
+}
+
+// Parse a PEM-encoded certificate chain.  The first certificate is the leaf,
+// and each following certificate is expected to certify the one before it.
+func parseCertificateChain(chain []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+
+	for {
+		var block *pem.Block
+		block, chain = pem.Decode(chain)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			return nil, fmt.Errorf("unexpected PEM block of type %q in certificate chain", block.Type)
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, errors.New("failed to parse PEM block containing the certificate")
+	}
+
+	return certs, nil
+}
+
+// Options for validating a certificate chain.  The chain is only required
+// to build to a trust anchor when TrustAnchors is set.
+type certificateValidationOptions struct {
+	TrustAnchors []*x509.Certificate
+	ExpiryWindow time.Duration
+	Now          time.Time
+}
+
+// Validate that a certificate chain builds to one of the trust anchors and
+// that none of its certificates is expired, or expiring within the window.
+// All the problems found are returned.
+func validateCertificateChain(certs []*x509.Certificate, opts certificateValidationOptions) []error {
+	var (
+		problems  []error
+		notBefore time.Time
+	)
+
+	for _, cert := range certs {
+		switch {
+		case opts.Now.Before(cert.NotBefore):
+			problems = append(problems, fmt.Errorf("certificate %q is not valid before %s", cert.Subject, cert.NotBefore.Format(time.RFC3339)))
+		case opts.Now.After(cert.NotAfter):
+			problems = append(problems, fmt.Errorf("certificate %q expired at %s", cert.Subject, cert.NotAfter.Format(time.RFC3339)))
+		case opts.Now.Add(opts.ExpiryWindow).After(cert.NotAfter):
+			problems = append(problems, fmt.Errorf("certificate %q expires at %s, within %s", cert.Subject, cert.NotAfter.Format(time.RFC3339), opts.ExpiryWindow))
+		}
+		if cert.NotBefore.After(notBefore) {
+			notBefore = cert.NotBefore
+		}
+	}
+
+	// Validity periods are reported above, so the chain is verified at a
+	// time when all its certificates are valid.
+	if len(opts.TrustAnchors) > 0 {
+		roots := x509.NewCertPool()
+		for _, cert := range opts.TrustAnchors {
+			roots.AddCert(cert)
+		}
+		intermediates := x509.NewCertPool()
+		for _, cert := range certs[1:] {
+			intermediates.AddCert(cert)
+		}
+		_, err := certs[0].Verify(x509.VerifyOptions{
+			Roots:         roots,
+			Intermediates: intermediates,
+			CurrentTime:   notBefore,
+			KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+		})
+		if err != nil {
+			problems = append(problems, fmt.Errorf("certificate chain does not build to a trust anchor: %w", err))
+		}
+	} else {
+		for i := 0; i < len(certs)-1; i++ {
+			if err := certs[i].CheckSignatureFrom(certs[i+1]); err != nil {
+				problems = append(problems, fmt.Errorf("certificate %q is not issued by the next certificate in the chain: %w", certs[i].Subject, err))
+			}
+		}
+	}
+
+	return problems
+}
+
+// Compare two public keys of any supported type.
+func publicKeysEqual(a, b crypto.PublicKey) bool {
+	key, ok := a.(interface{ Equal(crypto.PublicKey) bool })
+
+	return ok && key.Equal(b)
+}
+
+// Parse a JWK in JSON format.  If the JWK carries private members, only its
+// public part is returned.
+func parsePublicJWK(key []byte) (*jose.JSONWebKey, error) {
+	var jwk jose.JSONWebKey
+
+	if secp256k1JWK, err := parseSecp256k1JWK(key); err != nil {
+		return nil, err
+	} else if secp256k1JWK != nil {
+		if k, ok := secp256k1JWK.Key.(*ecdsa.PrivateKey); ok {
+			secp256k1JWK.Key = &k.PublicKey
+		}
+		return secp256k1JWK, nil
+	}
+
+	if err := json.Unmarshal(key, &jwk); err != nil {
+		return nil, err
+	}
+	if !jwk.Valid() {
+		return nil, errors.New("invalid JWK")
+	}
+	if !jwk.IsPublic() {
+		jwk = jwk.Public()
+		if jwk.Key == nil {
+			return nil, errors.New("JWK does not contain an asymmetric key")
+		}
+	}
+
+	return &jwk, nil
+}
+
+// Select a signing key from a JWK Set by key ID and return it in JSON format.
+// Keys intended for encryption by their use, or whose key_ops do not allow
+// the operation ("sign" or "verify"), are ignored.  When kid is empty, the set
+// must contain exactly one signing key.
+func selectSigningJWK(jwks []byte, kid string, operation string) ([]byte, error) {
+	var (
+		jwkSet     JWKSet
+		candidates []json.RawMessage
+	)
+
+	if err := json.Unmarshal(jwks, &jwkSet); err != nil {
 		return nil, err
 	}
 
-	return jwkJSON, nil
+	for _, key := range jwkSet.Keys {
+		var member struct {
+			KeyID  string   `json:"kid"`
+			Use    string   `json:"use"`
+			KeyOps []string `json:"key_ops"`
+		}
+		if err := json.Unmarshal(key, &member); err != nil {
+			return nil, err
+		}
+		if member.Use == "enc" || (kid != "" && member.KeyID != kid) {
+			continue
+		}
+		if len(member.KeyOps) > 0 && !slices.Contains(member.KeyOps, operation) {
+			continue
+		}
+		candidates = append(candidates, key)
+	}
+
+	switch {
+	case len(candidates) == 1:
+		return candidates[0], nil
+	case len(candidates) == 0 && kid != "":
+		return nil, fmt.Errorf("no signing key with key ID %q found in JWK Set", kid)
+	case len(candidates) == 0:
+		return nil, errors.New("no signing key found in JWK Set")
+	case kid != "":
+		return nil, fmt.Errorf("more than one signing key with key ID %q found in JWK Set", kid)
+	default:
+		return nil, errors.New("no key ID given and JWK Set contains more than one signing key")
+	}
 }
@@ -0,0 +1,246 @@
+// Copyright (c) Tze Liang
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"crypto/ecdsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/go-jose/go-jose/v4"
+)
+
+// A signer of a JWS, with the headers that are added to its signature.
+type jwsSigner struct {
+	key         PrivateKey
+	protected   map[string]interface{}
+	unprotected map[string]interface{}
+}
+
+// A signature in the JSON serialization of a JWS.
+type jwsSignature struct {
+	Protected string                 `json:"protected"`
+	Header    map[string]interface{} `json:"header,omitempty"`
+	Signature string                 `json:"signature"`
+}
+
+// The general JSON serialization of a JWS.  Payload is nil when detached.
+type jwsGeneralJSON struct {
+	Payload    *string        `json:"payload,omitempty"`
+	Signatures []jwsSignature `json:"signatures"`
+}
+
+// The flattened JSON serialization of a JWS, for a single signature.
+type jwsFlattenedJSON struct {
+	Payload *string `json:"payload,omitempty"`
+	jwsSignature
+}
+
+// Options for the serialization of a JWS.
+//
+// Serialization is one of "compact", "json" (general) or "flattened".
+// Detached leaves the payload out of the JWS (RFC 7515 appendix F).
+// Unencoded signs the payload as is rather than base64url encoded, by adding
+// the "b64" header to the "crit" header of every signature (RFC 7797).
+type jwsOptions struct {
+	Serialization string
+	Detached      bool
+	Unencoded     bool
+}
+
+// Sign a payload with every signer and return the JWS in the requested
+// serialization.  The compact and flattened serializations support a single
+// signer only.
+func signJWS(payload []byte, signers []jwsSigner, opts jwsOptions) (string, error) {
+	if len(signers) == 0 {
+		return "", errors.New("at least one signer is required")
+	}
+	if opts.Serialization != "json" && len(signers) > 1 {
+		return "", fmt.Errorf("%s serialization supports a single signature, use json serialization for %d signatures", opts.Serialization, len(signers))
+	}
+
+	encodedPayload := base64.RawURLEncoding.EncodeToString(payload)
+	if opts.Unencoded {
+		encodedPayload = string(payload)
+		// The payload delimits the segments of the compact serialization
+		// (RFC 7797 section 5.2).
+		if opts.Serialization == "compact" && !opts.Detached && strings.Contains(encodedPayload, ".") {
+			return "", errors.New("an unencoded payload containing '.' characters cannot be attached in compact serialization, detach it or use a JSON serialization")
+		}
+		if opts.Serialization != "compact" && !opts.Detached && !utf8.ValidString(encodedPayload) {
+			return "", errors.New("an unencoded payload must be valid UTF-8 to be attached in JSON serialization")
+		}
+	}
+
+	signatures := make([]jwsSignature, 0, len(signers))
+	for i, signer := range signers {
+		signature, err := signJWSSignature(payload, signer, opts.Unencoded)
+		if err != nil {
+			return "", fmt.Errorf("signature %d: %w", i, err)
+		}
+		signatures = append(signatures, signature)
+	}
+
+	var jsonPayload *string
+	if opts.Detached {
+		encodedPayload = ""
+	} else {
+		jsonPayload = &encodedPayload
+	}
+
+	var (
+		result []byte
+		err    error
+	)
+	switch opts.Serialization {
+	case "compact":
+		if len(signatures[0].Header) > 0 {
+			return "", errors.New("compact serialization does not support unprotected headers")
+		}
+		return strings.Join([]string{signatures[0].Protected, encodedPayload, signatures[0].Signature}, "."), nil
+	case "flattened":
+		result, err = json.Marshal(jwsFlattenedJSON{Payload: jsonPayload, jwsSignature: signatures[0]})
+	case "json":
+		result, err = json.Marshal(jwsGeneralJSON{Payload: jsonPayload, Signatures: signatures})
+	default:
+		return "", fmt.Errorf("unsupported serialization %q", opts.Serialization)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return string(result), nil
+}
+
+// Create a single signature over the payload.  The algorithm is selected
+// from the key and is always added to the protected header.
+func signJWSSignature(payload []byte, signer jwsSigner, unencoded bool) (jwsSignature, error) {
+	for _, name := range []string{"alg", "b64"} {
+		_, inProtected := signer.protected[name]
+		_, inUnprotected := signer.unprotected[name]
+		if inProtected || inUnprotected {
+			return jwsSignature{}, fmt.Errorf("header parameter %q is set by the provider", name)
+		}
+	}
+	// The critical header must be integrity protected (RFC 7515 section 4.1.11).
+	if _, ok := signer.unprotected["crit"]; ok {
+		return jwsSignature{}, errors.New(`header parameter "crit" must be in the protected headers`)
+	}
+	// Header parameter names must be disjoint between the protected and
+	// unprotected headers (RFC 7515 section 7.2.1).
+	names := make([]string, 0, len(signer.unprotected))
+	for name := range signer.unprotected {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		if _, ok := signer.protected[name]; ok {
+			return jwsSignature{}, fmt.Errorf("header parameter %q is set in both the protected and unprotected headers", name)
+		}
+	}
+
+	protected := make(map[string]interface{}, len(signer.protected)+2)
+	for name, value := range signer.protected {
+		protected[name] = value
+	}
+	crit, err := criticalHeaders(protected["crit"])
+	if err != nil {
+		return jwsSignature{}, err
+	}
+	if unencoded {
+		if !slices.Contains(crit, "b64") {
+			crit = append(crit, "b64")
+		}
+		protected["b64"] = false
+		protected["crit"] = crit
+	}
+	// Every critical header must be present (RFC 7515 section 4.1.11).
+	for _, name := range crit {
+		if _, ok := protected[name]; !ok {
+			return jwsSignature{}, fmt.Errorf("header parameter %q is listed in \"crit\" but missing from the protected headers", name)
+		}
+	}
+
+	opts := &jose.SignerOptions{}
+	for name, value := range protected {
+		opts.WithHeader(jose.HeaderKey(name), value)
+	}
+
+	signingKey := jose.SigningKey{
+		Algorithm: jose.SignatureAlgorithm(signer.key.algorithm()),
+		Key:       signer.key.cryptoKey(),
+	}
+	// go-jose does not support ES256K, whose signatures are made by the provider.
+	if k, ok := signingKey.Key.(*ecdsa.PrivateKey); ok && isSecp256k1(k.Curve) {
+		signingKey.Key = es256kSigner{k}
+	}
+
+	joseSigner, err := jose.NewSigner(signingKey, opts)
+	if err != nil {
+		return jwsSignature{}, err
+	}
+
+	object, err := joseSigner.Sign(payload)
+	if err != nil {
+		return jwsSignature{}, err
+	}
+
+	// The detached form is "<protected>..<signature>", regardless of the payload.
+	detached, err := object.DetachedCompactSerialize()
+	if err != nil {
+		return jwsSignature{}, err
+	}
+	parts := strings.Split(detached, ".")
+
+	return jwsSignature{
+		Protected: parts[0],
+		Header:    signer.unprotected,
+		Signature: parts[2],
+	}, nil
+}
+
+// Convert the value of a "crit" header, as decoded from JSON, into a list of
+// header parameter names.
+func criticalHeaders(value interface{}) ([]string, error) {
+	if value == nil {
+		return []string{}, nil
+	}
+	values, ok := value.([]interface{})
+	if !ok {
+		return nil, errors.New(`header parameter "crit" must be a list of strings`)
+	}
+
+	crit := make([]string, 0, len(values)+1)
+	for _, v := range values {
+		name, ok := v.(string)
+		if !ok {
+			return nil, errors.New(`header parameter "crit" must be a list of strings`)
+		}
+		crit = append(crit, name)
+	}
+
+	return crit, nil
+}
+
+// Parse an optional JSON object of header parameters.
+func parseJWSHeaders(headersJSON string) (map[string]interface{}, error) {
+	headers := map[string]interface{}{}
+	if headersJSON == "" {
+		return headers, nil
+	}
+	if err := json.Unmarshal([]byte(headersJSON), &headers); err != nil {
+		return nil, err
+	}
+	// A JSON null resets the map, which must be an object to add parameters.
+	if headers == nil {
+		return nil, errors.New("header parameters must be a JSON object")
+	}
+
+	return headers, nil
+}
@@ -0,0 +1,326 @@
+// Copyright (c) Tze Liang
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/des"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"hash"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// Generate a private key and return it in PEM format, using the encoding
+// that parsePrivateKey reads for each key type: PKCS #1 for RSA, SEC 1 for
+// ECDSA and PKCS #8 for Ed25519.  The public key is returned in PKIX PEM
+// format.
+func generateKeyPair(algorithm string, rsaBits int, ecdsaCurve string) ([]byte, []byte, error) {
+	var (
+		block  *pem.Block
+		pubKey crypto.PublicKey
+	)
+
+	switch algorithm {
+	case "RSA":
+		key, err := rsa.GenerateKey(rand.Reader, rsaBits)
+		if err != nil {
+			return nil, nil, err
+		}
+		block = &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}
+		pubKey = key.Public()
+	case "ECDSA":
+		var curve elliptic.Curve
+		switch ecdsaCurve {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, nil, fmt.Errorf("unsupported elliptic curve %q", ecdsaCurve)
+		}
+		key, err := ecdsa.GenerateKey(curve, rand.Reader)
+		if err != nil {
+			return nil, nil, err
+		}
+		der, err := x509.MarshalECPrivateKey(key)
+		if err != nil {
+			return nil, nil, err
+		}
+		block = &pem.Block{Type: "EC PRIVATE KEY", Bytes: der}
+		pubKey = key.Public()
+	case "Ed25519":
+		pub, key, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, nil, err
+		}
+		der, err := x509.MarshalPKCS8PrivateKey(key)
+		if err != nil {
+			return nil, nil, err
+		}
+		block = &pem.Block{Type: "PRIVATE KEY", Bytes: der}
+		pubKey = pub
+	default:
+		return nil, nil, fmt.Errorf("unsupported key algorithm %q", algorithm)
+	}
+
+	pubPEM, err := encodePublicKey(pubKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return pem.EncodeToMemory(block), pubPEM, nil
+}
+
+// Encode a public key in PKIX PEM format.
+func encodePublicKey(key crypto.PublicKey) ([]byte, error) {
+	var (
+		der []byte
+		err error
+	)
+	if k, ok := key.(*ecdsa.PublicKey); ok && isSecp256k1(k.Curve) {
+		der, err = marshalSecp256k1PublicKey(k)
+	} else {
+		der, err = x509.MarshalPKIXPublicKey(key)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), nil
+}
+
+// Describe a public key by its JWK key type, curve and size in bits.  The
+// size is the modulus size for RSA keys and the curve size otherwise.  The
+// curve is empty for RSA keys.
+func describePublicKey(key crypto.PublicKey) (string, string, int, error) {
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		return "RSA", "", k.N.BitLen(), nil
+	case *ecdsa.PublicKey:
+		return "EC", k.Curve.Params().Name, k.Curve.Params().BitSize, nil
+	case ed25519.PublicKey:
+		return "OKP", "Ed25519", 256, nil
+	default:
+		return "", "", 0, errors.New("unsupported public key type")
+	}
+}
+
+// The SubjectPublicKeyInfo structure of a PKIX public key (RFC 5280).
+type subjectPublicKeyInfo struct {
+	Algorithm pkix.AlgorithmIdentifier
+	PublicKey asn1.BitString
+}
+
+// An X25519 or X448 public key (RFC 7748), which can only be used for ECDH key
+// agreement.  go-jose supports neither curve and crypto/x509 does not support
+// X448, so these keys are parsed and marshalled by the provider.
+type xdhPublicKey struct {
+	Curve string
+	X     []byte
+}
+
+// Object identifiers and key sizes of the XDH curves (RFC 8410).
+var xdhCurves = map[string]struct {
+	oid  asn1.ObjectIdentifier
+	size int
+}{
+	"X25519": {asn1.ObjectIdentifier{1, 3, 101, 110}, 32},
+	"X448":   {asn1.ObjectIdentifier{1, 3, 101, 111}, 56},
+}
+
+func (k xdhPublicKey) Equal(x crypto.PublicKey) bool {
+	other, ok := x.(xdhPublicKey)
+
+	return ok && other.Curve == k.Curve && bytes.Equal(other.X, k.X)
+}
+
+// Parse a DER-encoded PKIX public key of an XDH curve.  It returns nil when
+// the key is of another type.
+func parseXDHPublicKey(der []byte) (crypto.PublicKey, error) {
+	var spki subjectPublicKeyInfo
+	if rest, err := asn1.Unmarshal(der, &spki); err != nil || len(rest) > 0 {
+		return nil, nil
+	}
+
+	for curve, params := range xdhCurves {
+		if !spki.Algorithm.Algorithm.Equal(params.oid) {
+			continue
+		}
+		if len(spki.PublicKey.Bytes) != params.size || spki.PublicKey.BitLength != 8*params.size {
+			return nil, fmt.Errorf("invalid %s public key", curve)
+		}
+		return xdhPublicKey{Curve: curve, X: spki.PublicKey.Bytes}, nil
+	}
+
+	return nil, nil
+}
+
+// Return the public key of a certificate, including XDH public keys.
+func certificatePublicKey(cert *x509.Certificate) crypto.PublicKey {
+	if key, err := parseXDHPublicKey(cert.RawSubjectPublicKeyInfo); err == nil && key != nil {
+		return key
+	}
+
+	return cert.PublicKey
+}
+
+// Object identifiers of the PBES2 encryption scheme (RFC 8018).
+var (
+	oidPBES2  = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 13}
+	oidPBKDF2 = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 12}
+
+	// Pseudorandom functions of PBKDF2, the default being HMAC-SHA1.
+	pbkdf2PRFs = map[string]func() hash.Hash{
+		"1.2.840.113549.2.7":  sha1.New,
+		"1.2.840.113549.2.8":  sha256.New224,
+		"1.2.840.113549.2.9":  sha256.New,
+		"1.2.840.113549.2.10": sha512.New384,
+		"1.2.840.113549.2.11": sha512.New,
+	}
+
+	// Block ciphers of PBES2 in CBC mode, with their key size in bytes.
+	pbes2Ciphers = map[string]struct {
+		newCipher func([]byte) (cipher.Block, error)
+		keySize   int
+	}{
+		"2.16.840.1.101.3.4.1.2":  {aes.NewCipher, 16},
+		"2.16.840.1.101.3.4.1.22": {aes.NewCipher, 24},
+		"2.16.840.1.101.3.4.1.42": {aes.NewCipher, 32},
+		"1.2.840.113549.3.7":      {des.NewTripleDESCipher, 24},
+	}
+)
+
+// The EncryptedPrivateKeyInfo structure of PKCS #8 (RFC 5208).
+type encryptedPrivateKeyInfo struct {
+	Algorithm     pkix.AlgorithmIdentifier
+	EncryptedData []byte
+}
+
+// The parameters of the PBES2 encryption scheme (RFC 8018 appendix A.4).
+type pbes2Params struct {
+	KeyDerivationFunc pkix.AlgorithmIdentifier
+	EncryptionScheme  pkix.AlgorithmIdentifier
+}
+
+// The parameters of the PBKDF2 key derivation function (RFC 8018 appendix
+// A.2).
+type pbkdf2Params struct {
+	Salt           []byte
+	IterationCount int
+	KeyLength      int                      `asn1:"optional"`
+	PRF            pkix.AlgorithmIdentifier `asn1:"optional"`
+}
+
+// Decrypt a passphrase protected private key in PEM format, either an
+// encrypted PKCS #8 key ("ENCRYPTED PRIVATE KEY") using PBES2 with PBKDF2, or
+// a legacy encrypted PEM block with a "DEK-Info" header.  The decrypted key is
+// returned in PEM format.  Unencrypted keys are returned as is.
+func decryptPrivateKey(key []byte, passphrase string) ([]byte, error) {
+	block, _ := pem.Decode(key)
+	if block == nil {
+		return nil, errors.New("failed to parse PEM block containing the key")
+	}
+
+	switch {
+	case block.Type == "ENCRYPTED PRIVATE KEY":
+		der, err := decryptPKCS8PrivateKey(block.Bytes, []byte(passphrase))
+		if err != nil {
+			return nil, err
+		}
+		return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+	case x509.IsEncryptedPEMBlock(block): //nolint:staticcheck // Legacy encrypted PEM blocks are still produced by OpenSSL.
+		der, err := x509.DecryptPEMBlock(block, []byte(passphrase)) //nolint:staticcheck // Legacy encrypted PEM blocks are still produced by OpenSSL.
+		if err != nil {
+			return nil, err
+		}
+		return pem.EncodeToMemory(&pem.Block{Type: block.Type, Bytes: der}), nil
+	default:
+		return key, nil
+	}
+}
+
+// Decrypt a DER-encoded EncryptedPrivateKeyInfo and return the PKCS #8
+// private key.
+func decryptPKCS8PrivateKey(der []byte, passphrase []byte) ([]byte, error) {
+	var (
+		info   encryptedPrivateKeyInfo
+		params pbes2Params
+		kdf    pbkdf2Params
+		iv     []byte
+	)
+
+	if rest, err := asn1.Unmarshal(der, &info); err != nil || len(rest) > 0 {
+		return nil, errors.New("invalid encrypted private key")
+	}
+	if !info.Algorithm.Algorithm.Equal(oidPBES2) {
+		return nil, fmt.Errorf("unsupported private key encryption scheme %s, only PBES2 is supported", info.Algorithm.Algorithm)
+	}
+	if _, err := asn1.Unmarshal(info.Algorithm.Parameters.FullBytes, &params); err != nil {
+		return nil, errors.New("invalid PBES2 parameters")
+	}
+	if !params.KeyDerivationFunc.Algorithm.Equal(oidPBKDF2) {
+		return nil, fmt.Errorf("unsupported key derivation function %s, only PBKDF2 is supported", params.KeyDerivationFunc.Algorithm)
+	}
+	if _, err := asn1.Unmarshal(params.KeyDerivationFunc.Parameters.FullBytes, &kdf); err != nil {
+		return nil, errors.New("invalid PBKDF2 parameters")
+	}
+
+	prf := sha1.New
+	if len(kdf.PRF.Algorithm) > 0 {
+		var ok bool
+		if prf, ok = pbkdf2PRFs[kdf.PRF.Algorithm.String()]; !ok {
+			return nil, fmt.Errorf("unsupported PBKDF2 pseudorandom function %s", kdf.PRF.Algorithm)
+		}
+	}
+	encryption, ok := pbes2Ciphers[params.EncryptionScheme.Algorithm.String()]
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key encryption algorithm %s", params.EncryptionScheme.Algorithm)
+	}
+	if kdf.KeyLength != 0 && kdf.KeyLength != encryption.keySize {
+		return nil, errors.New("invalid PBKDF2 parameters")
+	}
+	if _, err := asn1.Unmarshal(params.EncryptionScheme.Parameters.FullBytes, &iv); err != nil {
+		return nil, errors.New("invalid PBES2 parameters")
+	}
+
+	blockCipher, err := encryption.newCipher(pbkdf2.Key(passphrase, kdf.Salt, kdf.IterationCount, encryption.keySize, prf))
+	if err != nil {
+		return nil, err
+	}
+	size := blockCipher.BlockSize()
+	if len(iv) != size || len(info.EncryptedData) == 0 || len(info.EncryptedData)%size != 0 {
+		return nil, errors.New("invalid encrypted private key")
+	}
+
+	plaintext := make([]byte, len(info.EncryptedData))
+	cipher.NewCBCDecrypter(blockCipher, iv).CryptBlocks(plaintext, info.EncryptedData)
+
+	// Remove the PKCS #5 padding, which can only be checked loosely: a wrong
+	// passphrase is usually, but not always, detected here.
+	padding := int(plaintext[len(plaintext)-1])
+	if padding == 0 || padding > size || !bytes.Equal(plaintext[len(plaintext)-padding:], bytes.Repeat([]byte{byte(padding)}, padding)) {
+		return nil, errors.New("failed to decrypt private key, the passphrase may be incorrect")
+	}
+
+	return plaintext[:len(plaintext)-padding], nil
+}
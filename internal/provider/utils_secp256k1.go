@@ -0,0 +1,227 @@
+// Copyright (c) Tze Liang
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/go-jose/go-jose/v4"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// secp256k1 keys are used with the ES256K signing algorithm (RFC 8812).
+// Neither crypto/x509 nor go-jose support the curve, so these keys are
+// parsed, marshalled and signed by the provider.  The keys are regular
+// *ecdsa.PrivateKey and *ecdsa.PublicKey values on the secp256k1 curve.
+
+var (
+	oidPublicKeyECDSA = asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1}
+	oidCurveSecp256k1 = asn1.ObjectIdentifier{1, 3, 132, 0, 10}
+)
+
+// Report whether the curve is secp256k1.  Curves are compared by identity
+// rather than by size, as P-256 is also a 256 bits curve.
+func isSecp256k1(curve elliptic.Curve) bool {
+	return curve == secp256k1.S256()
+}
+
+// The ES256K signing method for JWTs.  It only accepts secp256k1 keys.
+type signingMethodES256K struct {
+	*jwt.SigningMethodECDSA
+}
+
+var jwtSigningMethodES256K = &signingMethodES256K{
+	&jwt.SigningMethodECDSA{Name: "ES256K", Hash: crypto.SHA256, KeySize: 32, CurveBits: 256},
+}
+
+func init() {
+	jwt.RegisterSigningMethod(jwtSigningMethodES256K.Alg(), func() jwt.SigningMethod {
+		return jwtSigningMethodES256K
+	})
+}
+
+func (m *signingMethodES256K) Sign(signingString string, key interface{}) ([]byte, error) {
+	if k, ok := key.(*ecdsa.PrivateKey); !ok || !isSecp256k1(k.Curve) {
+		return nil, jwt.ErrInvalidKeyType
+	}
+
+	return m.SigningMethodECDSA.Sign(signingString, key)
+}
+
+func (m *signingMethodES256K) Verify(signingString string, sig []byte, key interface{}) error {
+	if k, ok := key.(*ecdsa.PublicKey); !ok || !isSecp256k1(k.Curve) {
+		return jwt.ErrInvalidKeyType
+	}
+
+	return m.SigningMethodECDSA.Verify(signingString, sig, key)
+}
+
+// An opaque signer producing ES256K signatures for go-jose, which does not
+// support the algorithm.
+type es256kSigner struct {
+	key *ecdsa.PrivateKey
+}
+
+func (s es256kSigner) Public() *jose.JSONWebKey {
+	return &jose.JSONWebKey{Key: &s.key.PublicKey, Algorithm: "ES256K"}
+}
+
+func (s es256kSigner) Algs() []jose.SignatureAlgorithm {
+	return []jose.SignatureAlgorithm{"ES256K"}
+}
+
+func (s es256kSigner) SignPayload(payload []byte, alg jose.SignatureAlgorithm) ([]byte, error) {
+	return jwtSigningMethodES256K.Sign(string(payload), s.key)
+}
+
+// The ECPrivateKey structure of SEC 1 (RFC 5915).
+type ecPrivateKeyInfo struct {
+	Version       int
+	PrivateKey    []byte
+	NamedCurveOID asn1.ObjectIdentifier `asn1:"optional,explicit,tag:0"`
+	PublicKey     asn1.BitString        `asn1:"optional,explicit,tag:1"`
+}
+
+// The PrivateKeyInfo structure of PKCS #8 (RFC 5208).
+type pkcs8PrivateKeyInfo struct {
+	Version    int
+	Algorithm  pkix.AlgorithmIdentifier
+	PrivateKey []byte
+}
+
+// Parse a DER-encoded secp256k1 private key, in SEC 1 or PKCS #8 format.  It
+// returns nil when the key is of another type.
+func parseSecp256k1PrivateKey(der []byte) (*ecdsa.PrivateKey, error) {
+	var (
+		pkcs8 pkcs8PrivateKeyInfo
+		sec1  ecPrivateKeyInfo
+	)
+
+	if rest, err := asn1.Unmarshal(der, &pkcs8); err == nil && len(rest) == 0 {
+		var curve asn1.ObjectIdentifier
+		if !pkcs8.Algorithm.Algorithm.Equal(oidPublicKeyECDSA) {
+			return nil, nil
+		}
+		if _, err := asn1.Unmarshal(pkcs8.Algorithm.Parameters.FullBytes, &curve); err != nil || !curve.Equal(oidCurveSecp256k1) {
+			return nil, nil
+		}
+		if _, err := asn1.Unmarshal(pkcs8.PrivateKey, &sec1); err != nil {
+			return nil, errors.New("invalid secp256k1 private key")
+		}
+	} else if rest, err := asn1.Unmarshal(der, &sec1); err != nil || len(rest) > 0 || !sec1.NamedCurveOID.Equal(oidCurveSecp256k1) {
+		return nil, nil
+	}
+
+	var scalar secp256k1.ModNScalar
+	if len(sec1.PrivateKey) > 32 || scalar.SetByteSlice(sec1.PrivateKey) || scalar.IsZero() {
+		return nil, errors.New("invalid secp256k1 private key")
+	}
+
+	return secp256k1.NewPrivateKey(&scalar).ToECDSA(), nil
+}
+
+// Parse a DER-encoded PKIX secp256k1 public key.  It returns nil when the key
+// is of another type.
+func parseSecp256k1PublicKey(der []byte) (crypto.PublicKey, error) {
+	var (
+		spki  subjectPublicKeyInfo
+		curve asn1.ObjectIdentifier
+	)
+
+	if rest, err := asn1.Unmarshal(der, &spki); err != nil || len(rest) > 0 || !spki.Algorithm.Algorithm.Equal(oidPublicKeyECDSA) {
+		return nil, nil
+	}
+	if _, err := asn1.Unmarshal(spki.Algorithm.Parameters.FullBytes, &curve); err != nil || !curve.Equal(oidCurveSecp256k1) {
+		return nil, nil
+	}
+
+	key, err := secp256k1.ParsePubKey(spki.PublicKey.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	return key.ToECDSA(), nil
+}
+
+// Marshal a secp256k1 public key into DER-encoded PKIX format, with the point
+// uncompressed.
+func marshalSecp256k1PublicKey(key *ecdsa.PublicKey) ([]byte, error) {
+	curve, err := asn1.Marshal(oidCurveSecp256k1)
+	if err != nil {
+		return nil, err
+	}
+	point := append([]byte{4}, append(padScalar(key.X), padScalar(key.Y)...)...)
+
+	return asn1.Marshal(subjectPublicKeyInfo{
+		Algorithm: pkix.AlgorithmIdentifier{
+			Algorithm:  oidPublicKeyECDSA,
+			Parameters: asn1.RawValue{FullBytes: curve},
+		},
+		PublicKey: asn1.BitString{Bytes: point, BitLength: 8 * len(point)},
+	})
+}
+
+// Return a coordinate or private scalar of secp256k1 as a 32 bytes big-endian
+// value, as required in JWKs (RFC 7518 section 6.2.1.2).
+func padScalar(n *big.Int) []byte {
+	return n.FillBytes(make([]byte, 32))
+}
+
+// Parse a JWK of a secp256k1 key in JSON format, returning a private key when
+// the "d" member is present.  It returns nil when the JWK is of another type.
+func parseSecp256k1JWK(key []byte) (*jose.JSONWebKey, error) {
+	var raw struct {
+		Kty       string `json:"kty"`
+		Crv       string `json:"crv"`
+		KeyID     string `json:"kid"`
+		Algorithm string `json:"alg"`
+		Use       string `json:"use"`
+		X         string `json:"x"`
+		Y         string `json:"y"`
+		D         string `json:"d"`
+	}
+	if err := json.Unmarshal(key, &raw); err != nil || raw.Kty != "EC" || raw.Crv != "secp256k1" {
+		return nil, nil
+	}
+
+	x, errX := base64.RawURLEncoding.DecodeString(raw.X)
+	y, errY := base64.RawURLEncoding.DecodeString(raw.Y)
+	if errX != nil || errY != nil || len(x) != 32 || len(y) != 32 {
+		return nil, errors.New("invalid secp256k1 JWK")
+	}
+	pubKey, err := secp256k1.ParsePubKey(append([]byte{4}, append(x, y...)...))
+	if err != nil {
+		return nil, err
+	}
+
+	jwk := &jose.JSONWebKey{
+		Key:       pubKey.ToECDSA(),
+		KeyID:     raw.KeyID,
+		Algorithm: raw.Algorithm,
+		Use:       raw.Use,
+	}
+	if raw.D != "" {
+		var scalar secp256k1.ModNScalar
+		d, err := base64.RawURLEncoding.DecodeString(raw.D)
+		if err != nil || len(d) != 32 || scalar.SetByteSlice(d) || scalar.IsZero() {
+			return nil, errors.New("invalid secp256k1 JWK")
+		}
+		privateKey := secp256k1.NewPrivateKey(&scalar)
+		if !privateKey.PubKey().IsEqual(pubKey) {
+			return nil, errors.New("invalid secp256k1 JWK: the private key does not match the public key")
+		}
+		jwk.Key = privateKey.ToECDSA()
+	}
+
+	return jwk, nil
+}
@@ -0,0 +1,59 @@
+// Copyright (c) Tze Liang
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+// durationValidator validates that a string attribute is a duration in the
+// format accepted by time.ParseDuration, such as "30s", "15m" or "1h30m", and
+// optionally that it is positive.
+type durationValidator struct {
+	positive bool
+}
+
+var _ validator.String = durationValidator{}
+
+func (v durationValidator) Description(ctx context.Context) string {
+	if v.positive {
+		return "value must be a positive duration, such as \"30s\", \"15m\" or \"1h30m\""
+	}
+
+	return "value must be a duration, such as \"30s\", \"15m\" or \"1h30m\""
+}
+
+func (v durationValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v durationValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if d, err := time.ParseDuration(req.ConfigValue.ValueString()); err != nil || (v.positive && d <= 0) {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Duration",
+			fmt.Sprintf("Attribute %s %s, got: %q", req.Path, v.Description(ctx), req.ConfigValue.ValueString()),
+		)
+	}
+}
+
+// isDuration returns a validator which ensures that any configured string
+// value is a valid duration.
+func isDuration() validator.String {
+	return durationValidator{}
+}
+
+// isPositiveDuration returns a validator which ensures that any configured
+// string value is a valid duration greater than zero.
+func isPositiveDuration() validator.String {
+	return durationValidator{positive: true}
+}